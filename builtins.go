@@ -3,65 +3,121 @@ package musttag
 // builtins is a set of functions supported out of the box.
 var builtins = []Func{
 	// https://pkg.go.dev/encoding/json
-	{"encoding/json.Marshal", "json", 0, []string{"encoding/json.Marshaler", "encoding.TextMarshaler"}},
-	{"encoding/json.MarshalIndent", "json", 0, []string{"encoding/json.Marshaler", "encoding.TextMarshaler"}},
-	{"encoding/json.Unmarshal", "json", 1, []string{"encoding/json.Unmarshaler", "encoding.TextUnmarshaler"}},
-	{"(*encoding/json.Encoder).Encode", "json", 0, []string{"encoding/json.Marshaler", "encoding.TextMarshaler"}},
-	{"(*encoding/json.Decoder).Decode", "json", 0, []string{"encoding/json.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"encoding/json.Marshal", "json", 0, Encode, "", []string{"encoding/json.Marshaler", "encoding.TextMarshaler"}},
+	{"encoding/json.MarshalIndent", "json", 0, Encode, "", []string{"encoding/json.Marshaler", "encoding.TextMarshaler"}},
+	{"encoding/json.Unmarshal", "json", 1, Decode, "", []string{"encoding/json.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*encoding/json.Encoder).Encode", "json", 0, Encode, "", []string{"encoding/json.Marshaler", "encoding.TextMarshaler"}},
+	{"(*encoding/json.Decoder).Decode", "json", 0, Decode, "", []string{"encoding/json.Unmarshaler", "encoding.TextUnmarshaler"}},
 
 	// https://pkg.go.dev/encoding/xml
-	{"encoding/xml.Marshal", "xml", 0, []string{"encoding/xml.Marshaler", "encoding.TextMarshaler"}},
-	{"encoding/xml.MarshalIndent", "xml", 0, []string{"encoding/xml.Marshaler", "encoding.TextMarshaler"}},
-	{"encoding/xml.Unmarshal", "xml", 1, []string{"encoding/xml.Unmarshaler", "encoding.TextUnmarshaler"}},
-	{"(*encoding/xml.Encoder).Encode", "xml", 0, []string{"encoding/xml.Marshaler", "encoding.TextMarshaler"}},
-	{"(*encoding/xml.Decoder).Decode", "xml", 0, []string{"encoding/xml.Unmarshaler", "encoding.TextUnmarshaler"}},
-	{"(*encoding/xml.Encoder).EncodeElement", "xml", 0, []string{"encoding/xml.Marshaler", "encoding.TextMarshaler"}},
-	{"(*encoding/xml.Decoder).DecodeElement", "xml", 0, []string{"encoding/xml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"encoding/xml.Marshal", "xml", 0, Encode, "", []string{"encoding/xml.Marshaler", "encoding.TextMarshaler"}},
+	{"encoding/xml.MarshalIndent", "xml", 0, Encode, "", []string{"encoding/xml.Marshaler", "encoding.TextMarshaler"}},
+	{"encoding/xml.Unmarshal", "xml", 1, Decode, "", []string{"encoding/xml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*encoding/xml.Encoder).Encode", "xml", 0, Encode, "", []string{"encoding/xml.Marshaler", "encoding.TextMarshaler"}},
+	{"(*encoding/xml.Decoder).Decode", "xml", 0, Decode, "", []string{"encoding/xml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*encoding/xml.Encoder).EncodeElement", "xml", 0, Encode, "", []string{"encoding/xml.Marshaler", "encoding.TextMarshaler"}},
+	{"(*encoding/xml.Decoder).DecodeElement", "xml", 0, Decode, "", []string{"encoding/xml.Unmarshaler", "encoding.TextUnmarshaler"}},
 
 	// https://pkg.go.dev/gopkg.in/yaml.v3
-	{"gopkg.in/yaml.v3.Marshal", "yaml", 0, []string{"gopkg.in/yaml.v3.Marshaler"}},
-	{"gopkg.in/yaml.v3.Unmarshal", "yaml", 1, []string{"gopkg.in/yaml.v3.Unmarshaler"}},
-	{"(*gopkg.in/yaml.v3.Encoder).Encode", "yaml", 0, []string{"gopkg.in/yaml.v3.Marshaler"}},
-	{"(*gopkg.in/yaml.v3.Decoder).Decode", "yaml", 0, []string{"gopkg.in/yaml.v3.Unmarshaler"}},
+	{"gopkg.in/yaml.v3.Marshal", "yaml", 0, Encode, "", []string{"gopkg.in/yaml.v3.Marshaler"}},
+	{"gopkg.in/yaml.v3.Unmarshal", "yaml", 1, Decode, "", []string{"gopkg.in/yaml.v3.Unmarshaler"}},
+	{"(*gopkg.in/yaml.v3.Encoder).Encode", "yaml", 0, Encode, "", []string{"gopkg.in/yaml.v3.Marshaler"}},
+	{"(*gopkg.in/yaml.v3.Decoder).Decode", "yaml", 0, Decode, "", []string{"gopkg.in/yaml.v3.Unmarshaler"}},
 
 	// https://pkg.go.dev/github.com/BurntSushi/toml
-	{"github.com/BurntSushi/toml.Unmarshal", "toml", 1, []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
-	{"github.com/BurntSushi/toml.Decode", "toml", 1, []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
-	{"github.com/BurntSushi/toml.DecodeFS", "toml", 2, []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
-	{"github.com/BurntSushi/toml.DecodeFile", "toml", 1, []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
-	{"(*github.com/BurntSushi/toml.Encoder).Encode", "toml", 0, []string{"encoding.TextMarshaler"}},
-	{"(*github.com/BurntSushi/toml.Decoder).Decode", "toml", 0, []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"github.com/BurntSushi/toml.Unmarshal", "toml", 1, Decode, "", []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"github.com/BurntSushi/toml.Decode", "toml", 1, Decode, "", []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"github.com/BurntSushi/toml.DecodeFS", "toml", 2, Decode, "", []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"github.com/BurntSushi/toml.DecodeFile", "toml", 1, Decode, "", []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*github.com/BurntSushi/toml.Encoder).Encode", "toml", 0, Encode, "", []string{"encoding.TextMarshaler"}},
+	{"(*github.com/BurntSushi/toml.Decoder).Decode", "toml", 0, Decode, "", []string{"github.com/BurntSushi/toml.Unmarshaler", "encoding.TextUnmarshaler"}},
 
 	// https://pkg.go.dev/github.com/mitchellh/mapstructure
-	{"github.com/mitchellh/mapstructure.Decode", "mapstructure", 1, nil},
-	{"github.com/mitchellh/mapstructure.DecodeMetadata", "mapstructure", 1, nil},
-	{"github.com/mitchellh/mapstructure.WeakDecode", "mapstructure", 1, nil},
-	{"github.com/mitchellh/mapstructure.WeakDecodeMetadata", "mapstructure", 1, nil},
+	{"github.com/mitchellh/mapstructure.Decode", "mapstructure", 1, Decode, "", nil},
+	{"github.com/mitchellh/mapstructure.DecodeMetadata", "mapstructure", 1, Decode, "", nil},
+	{"github.com/mitchellh/mapstructure.WeakDecode", "mapstructure", 1, Decode, "", nil},
+	{"github.com/mitchellh/mapstructure.WeakDecodeMetadata", "mapstructure", 1, Decode, "", nil},
 
 	// https://pkg.go.dev/github.com/jmoiron/sqlx
-	{"github.com/jmoiron/sqlx.Get", "db", 1, []string{"database/sql.Scanner"}},
-	{"github.com/jmoiron/sqlx.GetContext", "db", 2, []string{"database/sql.Scanner"}},
-	{"github.com/jmoiron/sqlx.Select", "db", 1, []string{"database/sql.Scanner"}},
-	{"github.com/jmoiron/sqlx.SelectContext", "db", 2, []string{"database/sql.Scanner"}},
-	{"github.com/jmoiron/sqlx.StructScan", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Conn).GetContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Conn).SelectContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.DB).Get", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.DB).GetContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.DB).Select", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.DB).SelectContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.NamedStmt).Get", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.NamedStmt).GetContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.NamedStmt).Select", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.NamedStmt).SelectContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Row).StructScan", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Rows).StructScan", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Stmt).Get", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Stmt).GetContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Stmt).Select", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Stmt).SelectContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Tx).Get", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Tx).GetContext", "db", 1, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Tx).Select", "db", 0, []string{"database/sql.Scanner"}},
-	{"(*github.com/jmoiron/sqlx.Tx).SelectContext", "db", 1, []string{"database/sql.Scanner"}},
+	{"github.com/jmoiron/sqlx.Get", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"github.com/jmoiron/sqlx.GetContext", "db", 2, Decode, "", []string{"database/sql.Scanner"}},
+	{"github.com/jmoiron/sqlx.Select", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"github.com/jmoiron/sqlx.SelectContext", "db", 2, Decode, "", []string{"database/sql.Scanner"}},
+	{"github.com/jmoiron/sqlx.StructScan", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Conn).GetContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Conn).SelectContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.DB).Get", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.DB).GetContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.DB).Select", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.DB).SelectContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.NamedStmt).Get", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.NamedStmt).GetContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.NamedStmt).Select", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.NamedStmt).SelectContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Row).StructScan", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Rows).StructScan", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Stmt).Get", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Stmt).GetContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Stmt).Select", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Stmt).SelectContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Tx).Get", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Tx).GetContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Tx).Select", "db", 0, Decode, "", []string{"database/sql.Scanner"}},
+	{"(*github.com/jmoiron/sqlx.Tx).SelectContext", "db", 1, Decode, "", []string{"database/sql.Scanner"}},
+
+	// https://pkg.go.dev/github.com/anacrolix/torrent/bencode
+	{"github.com/anacrolix/torrent/bencode.Marshal", "bencode", 0, Encode, "", []string{"github.com/anacrolix/torrent/bencode.Marshaler"}},
+	{"github.com/anacrolix/torrent/bencode.Unmarshal", "bencode", 1, Decode, "", []string{"github.com/anacrolix/torrent/bencode.Unmarshaler"}},
+	{"(*github.com/anacrolix/torrent/bencode.Encoder).Encode", "bencode", 0, Encode, "", []string{"github.com/anacrolix/torrent/bencode.Marshaler"}},
+	{"(*github.com/anacrolix/torrent/bencode.Decoder).Decode", "bencode", 0, Decode, "", []string{"github.com/anacrolix/torrent/bencode.Unmarshaler"}},
+
+	// https://pkg.go.dev/github.com/gorilla/schema
+	{"(*github.com/gorilla/schema.Encoder).Encode", "schema", 0, Encode, "", nil},
+	{"(*github.com/gorilla/schema.Decoder).Decode", "schema", 0, Decode, "", []string{"encoding.TextUnmarshaler"}},
+
+	// https://pkg.go.dev/github.com/gin-gonic/gin
+	{"(*github.com/gin-gonic/gin.Context).BindJSON", "json", 0, Decode, "", []string{"encoding/json.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).BindXML", "xml", 0, Decode, "", []string{"encoding/xml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).BindYAML", "yaml", 0, Decode, "", []string{"gopkg.in/yaml.v3.Unmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).BindTOML", "toml", 0, Decode, "", []string{"encoding.TextUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).BindQuery", "form", 0, Decode, "", []string{"github.com/gin-gonic/gin/binding.BindUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).BindHeader", "header", 0, Decode, "", []string{"github.com/gin-gonic/gin/binding.BindUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).BindUri", "uri", 0, Decode, "", []string{"github.com/gin-gonic/gin/binding.BindUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).ShouldBindJSON", "json", 0, Decode, "", []string{"encoding/json.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).ShouldBindXML", "xml", 0, Decode, "", []string{"encoding/xml.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).ShouldBindYAML", "yaml", 0, Decode, "", []string{"gopkg.in/yaml.v3.Unmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).ShouldBindTOML", "toml", 0, Decode, "", []string{"encoding.TextUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).ShouldBindQuery", "form", 0, Decode, "", []string{"github.com/gin-gonic/gin/binding.BindUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).ShouldBindHeader", "header", 0, Decode, "", []string{"github.com/gin-gonic/gin/binding.BindUnmarshaler"}},
+	{"(*github.com/gin-gonic/gin.Context).ShouldBindUri", "uri", 0, Decode, "", []string{"github.com/gin-gonic/gin/binding.BindUnmarshaler"}},
+
+	// https://pkg.go.dev/github.com/go-playground/form/v4
+	{"(*github.com/go-playground/form/v4.Encoder).Encode", "form", 0, Encode, "", nil},
+	{"(*github.com/go-playground/form/v4.Decoder).Decode", "form", 0, Decode, "", nil},
+
+	// https://pkg.go.dev/github.com/google/go-querystring/query
+	{"github.com/google/go-querystring/query.Values", "url", 0, Encode, "", nil},
+
+	// https://pkg.go.dev/encoding/asn1
+	{"encoding/asn1.Marshal", "asn1", 0, Encode, "", nil},
+	{"encoding/asn1.MarshalWithParams", "asn1", 0, Encode, "", nil},
+	{"encoding/asn1.Unmarshal", "asn1", 1, Decode, "", nil},
+	{"encoding/asn1.UnmarshalWithParams", "asn1", 1, Decode, "", nil},
+
+	// https://pkg.go.dev/github.com/redis/go-redis/v9
+	{"(github.com/redis/go-redis/v9.cmdable).HSet", "redis", 2, Encode, "", nil},
+	{"(github.com/redis/go-redis/v9.cmdable).HMSet", "redis", 2, Encode, "", nil},
+
+	// https://pkg.go.dev/github.com/caarlos0/env/v11
+	{"github.com/caarlos0/env/v11.Parse", "env", 0, Decode, "", nil},
+	{"github.com/caarlos0/env/v11.ParseWithOptions", "env", 0, Decode, "", nil},
+	{"github.com/caarlos0/env/v11.ParseAs", "env", ResultPos, Decode, "", nil},
+	{"github.com/caarlos0/env/v11.ParseAsWithOptions", "env", ResultPos, Decode, "", nil},
+
+	// https://pkg.go.dev/howett.net/plist
+	{"howett.net/plist.Marshal", "plist", 0, Encode, "", []string{"howett.net/plist.Marshaler", "encoding.TextMarshaler"}},
+	{"howett.net/plist.MarshalIndent", "plist", 0, Encode, "", []string{"howett.net/plist.Marshaler", "encoding.TextMarshaler"}},
+	{"howett.net/plist.Unmarshal", "plist", 1, Decode, "", []string{"howett.net/plist.Unmarshaler", "encoding.TextUnmarshaler"}},
+	{"(*howett.net/plist.Encoder).Encode", "plist", 0, Encode, "", []string{"howett.net/plist.Marshaler", "encoding.TextMarshaler"}},
+	{"(*howett.net/plist.Decoder).Decode", "plist", 0, Decode, "", []string{"howett.net/plist.Unmarshaler", "encoding.TextUnmarshaler"}},
 }
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfig is loaded from the working directory unless the -config flag is given.
+const defaultConfig = ".musttag.yaml"
+
+// configPath returns the value of the -config flag among the given command line flags,
+// or the default config file if it exists.
+func configPath(args []string) (string, error) {
+loop:
+	for i, arg := range args {
+		switch {
+		case arg == "-config" || arg == "--config":
+			if i+1 == len(args) {
+				return "", errors.New("flag needs an argument: -config")
+			}
+			return args[i+1], nil
+		case strings.HasPrefix(arg, "-config=") || strings.HasPrefix(arg, "--config="):
+			_, path, _ := strings.Cut(arg, "=")
+			return path, nil
+		case arg == "--" || !strings.HasPrefix(arg, "-"):
+			break loop // the rest are package patterns.
+		}
+	}
+
+	if _, err := os.Stat(defaultConfig); errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return defaultConfig, nil
+}
+
+// configFunc is an entry of the functions list, the same as the -fn flag.
+type configFunc struct {
+	Name    string `yaml:"name"`
+	Tag     string `yaml:"tag"`
+	ArgPos  string `yaml:"arg-pos"` // A number or ret, 0 by default.
+	Message string `yaml:"message"`
+}
+
+// loadConfig reads the config file and sets the flags accordingly.
+// The keys of the file are the names of the flags, except for the list of functions:
+//
+//	functions:
+//	  - name: example.com/custom.Marshal
+//	    tag: custom
+//	    arg-pos: 0
+//	    message: add a custom tag
+//	direction: decode
+//	only-tags: [json, yaml]
+//	accept-tags:
+//	  - [json, codec]
+//
+// A list is the same as a comma-separated value, and a list of lists is the same as passing the flag several times.
+// The flags given on the command line are applied after the file.
+func loadConfig(flags *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil // an empty file.
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s:%d: the config must be a mapping", path, root.Line)
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		if key.Value == "functions" {
+			if err := setFunctions(flags, value); err != nil {
+				return fmt.Errorf("%s:%d: %w", path, value.Line, err)
+			}
+			continue
+		}
+
+		if key.Value == "fn" {
+			return fmt.Errorf("%s:%d: use the functions list instead of the fn option", path, key.Line)
+		}
+		if key.Value == "config" || flags.Lookup(key.Value) == nil {
+			return fmt.Errorf("%s:%d: unknown option %q", path, key.Line, key.Value)
+		}
+
+		values, err := flagValues(value)
+		if err != nil {
+			return fmt.Errorf("%s:%d: option %q: %w", path, value.Line, key.Value, err)
+		}
+		for _, v := range values {
+			if err := flags.Set(key.Value, v); err != nil {
+				return fmt.Errorf("%s:%d: option %q: invalid value %q: %w", path, value.Line, key.Value, v, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func setFunctions(flags *flag.FlagSet, node *yaml.Node) error {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var funcs []configFunc
+	if err := dec.Decode(&funcs); err != nil {
+		return fmt.Errorf("functions: %w", err)
+	}
+
+	for _, fn := range funcs {
+		if fn.ArgPos == "" {
+			fn.ArgPos = "0"
+		}
+		value := fn.Name + ":" + fn.Tag + ":" + fn.ArgPos
+		if fn.Message != "" {
+			value += ":" + fn.Message
+		}
+		if err := flags.Set("fn", value); err != nil {
+			return fmt.Errorf("functions: %s: %w", fn.Name, err)
+		}
+	}
+	return nil
+}
+
+// flagValues converts the value of an option to the values of the flag.
+func flagValues(node *yaml.Node) ([]string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}, nil
+	case yaml.SequenceNode:
+		if len(node.Content) > 0 && node.Content[0].Kind == yaml.SequenceNode {
+			var values []string
+			for _, elem := range node.Content {
+				joined, err := joinScalars(elem)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, joined)
+			}
+			return values, nil
+		}
+		joined, err := joinScalars(node)
+		if err != nil {
+			return nil, err
+		}
+		return []string{joined}, nil
+	case yaml.MappingNode: // e.g. `tag-case: {json: camel}`.
+		var values []string
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode || value.Kind != yaml.ScalarNode {
+				return nil, errors.New("a mapping of scalars is expected")
+			}
+			values = append(values, key.Value+":"+value.Value)
+		}
+		return values, nil
+	default:
+		return nil, errors.New("unsupported value")
+	}
+}
+
+func joinScalars(node *yaml.Node) (string, error) {
+	if node.Kind != yaml.SequenceNode {
+		return "", errors.New("a list is expected")
+	}
+	values := make([]string, len(node.Content))
+	for i, elem := range node.Content {
+		if elem.Kind != yaml.ScalarNode {
+			return "", errors.New("a list of scalars is expected")
+		}
+		values[i] = elem.Value
+	}
+	return strings.Join(values, ","), nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go-simpler.org/musttag"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/packages"
+)
+
+// jsonDiagnostic is a single entry of the -json output.
+// NOTE: the schema is a part of the public API, keep it backward compatible.
+type jsonDiagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// hasJSONFlag reports whether the -json flag is among the given command line flags.
+func hasJSONFlag(fs *flag.FlagSet, args []string) bool { return hasBoolFlag(fs, args, "json") }
+
+// hasBoolFlag reports whether the boolean flag is set among the given command line flags.
+// The values of the flags of fs are skipped, e.g. `-direction encode -json ./...`.
+func hasBoolFlag(fs *flag.FlagSet, args []string, name string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-"+name || arg == "--"+name || arg == "-"+name+"=true" || arg == "--"+name+"=true":
+			return true
+		case arg == "--" || !strings.HasPrefix(arg, "-"):
+			return false // the rest are package patterns.
+		case takesValue(fs, arg):
+			i++ // the next argument is the value of the flag.
+		}
+	}
+	return false
+}
+
+// takesValue reports whether the flag (e.g. -direction) takes the next argument as its value.
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	if strings.Contains(name, "=") {
+		return false // e.g. -direction=encode.
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return false // an unknown flag, reported by the parser later.
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !b.IsBoolFlag()
+}
+
+// runJSON analyzes the packages matching the patterns from args and writes the diagnostics to w as JSON.
+// It overrides the builtin -json flag, whose output has no per-field information.
+func runJSON(analyzer *analysis.Analyzer, args []string, w io.Writer) error {
+	diags, err := analyze(analyzer, args)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(diags)
+}
+
+// newFlagSet returns the command line flags of the -json and -sarif outputs:
+// the flags of the analyzer and the standard flags of singlechecker.
+func newFlagSet(analyzer *analysis.Analyzer) *flag.FlagSet {
+	fs := flag.NewFlagSet("musttag", flag.ContinueOnError)
+	fs.Bool("json", false, "emit JSON output")
+	fs.Bool("sarif", false, "emit SARIF output")
+	fs.String("config", defaultConfig, "load the options from the given file") // already loaded.
+	fs.Bool("test", true, "indicates whether test files should be analyzed, too")
+	fs.Var(unsupportedFlag{}, "fix", "apply all suggested fixes")
+
+	// the rest of the standard flags do not affect the output.
+	fs.Int("c", -1, "display offending line with this many lines of context")
+	fs.String("tags", "", "no effect (deprecated)")
+	for _, name := range [...]string{"debug", "cpuprofile", "memprofile", "trace"} {
+		fs.String(name, "", "no effect with -json and -sarif")
+	}
+
+	analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	return fs
+}
+
+// unsupportedFlag is a standard flag of singlechecker that cannot be combined with -json and -sarif.
+type unsupportedFlag struct{}
+
+func (unsupportedFlag) String() string   { return "" }
+func (unsupportedFlag) IsBoolFlag() bool { return true }
+func (unsupportedFlag) Set(string) error { return errors.New("not supported with -json and -sarif") }
+
+// analyze runs the analyzer on the packages matching the patterns from args, which may also contain its flags.
+// The paths of the files are relative to the working directory, if possible.
+func analyze(analyzer *analysis.Analyzer, args []string) ([]jsonDiagnostic, error) {
+	fs := newFlagSet(analyzer)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.Lookup("count-only").Value.String() == "true" {
+		// the summary is a diagnostic without a field, so it does not fit the schema.
+		return nil, errors.New("-count-only is not supported with -json and -sarif")
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports |
+			packages.NeedTypes | packages.NeedTypesSizes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Tests: fs.Lookup("test").Value.(flag.Getter).Get().(bool),
+	}
+	if len(analyzer.FactTypes) > 0 {
+		// the facts are exported by the dependencies, so they are analyzed from source, too.
+		// Otherwise, only their types are needed, which are loaded from export data.
+		cfg.Mode |= packages.NeedDeps
+	}
+	pkgs, err := packages.Load(cfg, fs.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("loading packages: %d error(s)", n)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	// the dependencies are analyzed first, so that the facts about them are known to the importers.
+	var order []*packages.Package
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) { order = append(order, pkg) })
+
+	facts := make(factStore)
+	seen := make(map[jsonDiagnostic]struct{})
+	diags := []jsonDiagnostic{} // an empty list rather than null.
+	for _, pkg := range order {
+		if pkg.TypesInfo == nil {
+			continue // e.g. a package loaded from export data.
+		}
+		pass := &analysis.Pass{
+			Analyzer:   analyzer,
+			Fset:       pkg.Fset,
+			Files:      pkg.Syntax,
+			Pkg:        pkg.Types,
+			TypesInfo:  pkg.TypesInfo,
+			TypesSizes: pkg.TypesSizes,
+			ResultOf:   map[*analysis.Analyzer]any{inspect.Analyzer: inspector.New(pkg.Syntax)},
+			ReadFile:   os.ReadFile,
+			Report:     func(analysis.Diagnostic) {}, // the result is used instead.
+
+			ImportObjectFact: facts.importObjectFact,
+			ExportObjectFact: facts.exportObjectFact,
+		}
+		res, err := analyzer.Run(pass)
+		if err != nil {
+			return nil, fmt.Errorf("analyzing %s: %w", pkg.PkgPath, err)
+		}
+		if !slices.Contains(pkgs, pkg) {
+			continue // only the facts of a dependency are needed.
+		}
+		for _, finding := range res.(*musttag.Result).Findings {
+			diag := newJSONDiagnostic(pkg.Fset, wd, finding)
+			if _, ok := seen[diag]; ok {
+				continue // e.g. reported again for the test variant of the package.
+			}
+			seen[diag] = struct{}{}
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags, nil
+}
+
+// factKey identifies a fact of a single type about an object.
+type factKey struct {
+	obj types.Object
+	typ reflect.Type
+}
+
+// factStore is an in-memory store of the object facts exported by the analyzer across packages.
+type factStore map[factKey]analysis.Fact
+
+func (s factStore) importObjectFact(obj types.Object, fact analysis.Fact) bool {
+	stored, ok := s[factKey{obj, reflect.TypeOf(fact)}]
+	if ok {
+		reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+	}
+	return ok
+}
+
+func (s factStore) exportObjectFact(obj types.Object, fact analysis.Fact) {
+	s[factKey{obj, reflect.TypeOf(fact)}] = fact
+}
+
+func newJSONDiagnostic(fset *token.FileSet, wd string, finding musttag.Finding) jsonDiagnostic {
+	posn := fset.Position(finding.Pos)
+	file := posn.Filename
+	if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+		file = filepath.ToSlash(rel)
+	}
+	return jsonDiagnostic{
+		File:    file,
+		Line:    posn.Line,
+		Col:     posn.Column,
+		Field:   finding.Field,
+		Tag:     finding.Tag,
+		Message: finding.Message,
+	}
+}
@@ -13,9 +13,40 @@ import (
 var version = "dev" // injected at build time.
 
 func main() {
+	analyzer := musttag.New()
+
+	path, err := configPath(os.Args[1:])
+	if err == nil && path != "" {
+		err = loadConfig(&analyzer.Flags, path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "musttag: %v\n", err)
+		os.Exit(1)
+	}
+
+	// override the builtin -json flag.
+	fs := newFlagSet(analyzer)
+	if hasJSONFlag(fs, os.Args[1:]) {
+		if err := runJSON(analyzer, os.Args[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "musttag: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if hasBoolFlag(fs, os.Args[1:], "sarif") {
+		if err := runSARIF(analyzer, os.Args[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "musttag: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// override the builtin -V flag.
 	flag.Var(versionFlag{}, "V", "print version and exit")
-	singlechecker.Main(musttag.New())
+	flag.String("config", defaultConfig, "load the options from the given file")
+	flag.Bool("sarif", false, "emit SARIF output")
+	singlechecker.Main(analyzer)
 }
 
 type versionFlag struct{}
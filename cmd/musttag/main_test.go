@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-simpler.org/assert"
+	. "go-simpler.org/assert/EF"
+	"go-simpler.org/musttag"
+	"golang.org/x/tools/go/packages"
+)
+
+func Test_hasJSONFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"./..."}, false},
+		{[]string{"-json", "./..."}, true},
+		{[]string{"-fn=a.B:c:0", "--json", "./..."}, true},
+		{[]string{"./...", "-json"}, false},
+		{[]string{"--", "-json"}, false},
+		{[]string{"-sarif", "./..."}, false},
+		{[]string{"-direction", "encode", "-json", "./..."}, true},
+		{[]string{"--max-depth", "2", "--json", "./..."}, true},
+		{[]string{"-config", "musttag.yaml", "-json", "./..."}, true},
+		{[]string{"-direction=encode", "-per-field", "-json", "./..."}, true},
+		{[]string{"-per-field", "./...", "-json"}, false},
+		{[]string{"-tags", "integration", "-c", "2", "-json", "./..."}, true},
+		{[]string{"-test=false", "-json", "./..."}, true},
+	}
+
+	fs := newFlagSet(musttag.New())
+	for _, test := range tests {
+		got := hasJSONFlag(fs, test.args)
+		assert.Equal[E](t, got, test.want)
+	}
+}
+
+func Test_runJSON(t *testing.T) {
+	skipWithoutExportData(t)
+
+	golden, err := os.ReadFile(filepath.Join("testdata", "golden.json"))
+	assert.NoErr[F](t, err)
+
+	wd, err := os.Getwd()
+	assert.NoErr[F](t, err)
+	err = os.Chdir(filepath.Join("testdata", "src"))
+	assert.NoErr[F](t, err)
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var buf bytes.Buffer
+	err = runJSON(musttag.New(), []string{"-json", "-unique-names", "./..."}, &buf)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, buf.String(), string(golden))
+}
+
+func Test_runSARIF(t *testing.T) {
+	skipWithoutExportData(t)
+
+	golden, err := os.ReadFile(filepath.Join("testdata", "golden.sarif"))
+	assert.NoErr[F](t, err)
+
+	wd, err := os.Getwd()
+	assert.NoErr[F](t, err)
+	err = os.Chdir(filepath.Join("testdata", "src"))
+	assert.NoErr[F](t, err)
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var buf bytes.Buffer
+	err = runSARIF(musttag.New(), []string{"-sarif", "-unique-names", "./..."}, &buf)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, buf.String(), string(golden))
+}
+
+func Test_analyzeFacts(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoErr[F](t, err)
+	err = os.Chdir(filepath.Join("testdata", "facts"))
+	assert.NoErr[F](t, err)
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	// the struct returned behind an interface by the decoder of another package is known via facts.
+	diags, err := analyze(musttag.New(), []string{"-fn=example.com/facts/events.Decode:json:ret", "./..."})
+	assert.NoErr[F](t, err)
+	assert.Equal[F](t, len(diags), 3)
+	for _, diag := range diags {
+		assert.Equal[E](t, diag.Field, "Kind")
+	}
+	assert.Equal[E](t, diags[0].File, "events/events.go") // the dependencies are analyzed first.
+	assert.Equal[E](t, diags[0].Line, 13)
+	assert.Equal[E](t, diags[1].File, "app.go")
+	assert.Equal[E](t, diags[1].Line, 10)
+	assert.Equal[E](t, diags[2].File, "app.go")
+	assert.Equal[E](t, diags[2].Line, 14)
+}
+
+func Test_analyzeTests(t *testing.T) {
+	skipWithoutExportData(t)
+
+	wd, err := os.Getwd()
+	assert.NoErr[F](t, err)
+	err = os.Chdir(filepath.Join("testdata", "tests"))
+	assert.NoErr[F](t, err)
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"./..."}, []string{"Name", "Email"}}, // the findings of the test variant are not duplicated.
+		{[]string{"-test=false", "./..."}, []string{"Name"}},
+		{[]string{"-tags", "integration", "-c", "2", "-debug", "fp", "./..."}, []string{"Name", "Email"}},
+	}
+
+	for _, test := range tests {
+		diags, err := analyze(musttag.New(), test.args)
+		assert.NoErr[F](t, err)
+		var fields []string
+		for _, diag := range diags {
+			fields = append(fields, diag.Field)
+		}
+		assert.Equal[E](t, fields, test.want)
+	}
+
+	_, err = analyze(musttag.New(), []string{"-fix", "./..."})
+	assert.Equal[E](t, err.Error(), `invalid boolean flag fix: not supported with -json and -sarif`)
+}
+
+func Test_analyzeCountOnly(t *testing.T) {
+	_, err := analyze(musttag.New(), []string{"-count-only", "-json", "./..."})
+	assert.Equal[E](t, err.Error(), "-count-only is not supported with -json and -sarif")
+
+	// e.g. set in the config.
+	analyzer := musttag.New()
+	err = analyzer.Flags.Set("count-only", "true")
+	assert.NoErr[F](t, err)
+	var buf bytes.Buffer
+	err = runSARIF(analyzer, []string{"-sarif", "./..."}, &buf)
+	assert.Equal[E](t, err.Error(), "-count-only is not supported with -json and -sarif")
+	assert.Equal[E](t, buf.Len(), 0)
+}
+
+func Test_configPath(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"./..."}, ""},
+		{[]string{"-config", "a.yaml", "./..."}, "a.yaml"},
+		{[]string{"-json", "--config=b.yaml", "./..."}, "b.yaml"},
+		{[]string{"./...", "-config=c.yaml"}, ""},
+	}
+
+	for _, test := range tests {
+		got, err := configPath(test.args)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, got, test.want)
+	}
+}
+
+func Test_loadConfig(t *testing.T) {
+	skipWithoutExportData(t)
+
+	analyzer := musttag.New()
+	err := loadConfig(&analyzer.Flags, filepath.Join("testdata", "musttag.yaml"))
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, analyzer.Flags.Lookup("unique-names").Value.String(), "true")
+	assert.Equal[E](t, analyzer.Flags.Lookup("max-depth").Value.String(), "2")
+
+	// only the xml and enc tags are reported, so the json reports are gone.
+	wd, err := os.Getwd()
+	assert.NoErr[F](t, err)
+	err = os.Chdir(filepath.Join("testdata", "src"))
+	assert.NoErr[F](t, err)
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var buf bytes.Buffer
+	err = runJSON(analyzer, []string{"-json", "./..."}, &buf)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, buf.String(), "[]\n")
+}
+
+func Test_loadConfig_errors(t *testing.T) {
+	tests := []struct {
+		config, want string
+	}{
+		{"unknown: true", `musttag.yaml:1: unknown option "unknown"`},
+		{"fn: [a.B:c:0]", `musttag.yaml:1: use the functions list instead of the fn option`},
+		{"direction: sideways", `musttag.yaml:1: option "direction": invalid value "sideways": invalid syntax`},
+		{"functions:\n  - name: a.B\n    tags: c", "musttag.yaml:2: functions: yaml: unmarshal errors:\n  line 2: field tags not found in type main.configFunc"},
+		{"functions:\n  - name: a.B\n    tag: c\n    arg-pos: last", `musttag.yaml:2: functions: a.B: strconv.Atoi: parsing "last": invalid syntax`},
+		{"- json", "musttag.yaml:1: the config must be a mapping"},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "musttag.yaml")
+		err := os.WriteFile(path, []byte(test.config), 0o644)
+		assert.NoErr[F](t, err)
+
+		err = loadConfig(&musttag.New().Flags, path)
+		assert.Equal[E](t, strings.TrimPrefix(err.Error(), filepath.Dir(path)+string(filepath.Separator)), test.want)
+	}
+}
+
+// skipWithoutExportData skips the test if golang.org/x/tools cannot read the export data of the go toolchain,
+// which the dependencies are loaded from unless facts are needed, as by singlechecker.
+func skipWithoutExportData(t *testing.T) {
+	t.Helper()
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedTypes}, "errors")
+	if err != nil || len(pkgs) != 1 || len(pkgs[0].Errors) > 0 {
+		t.Skip("the export data of the go toolchain is not supported by golang.org/x/tools")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// sarifLog is the root of the -sarif output, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+// Only the properties required by code scanning integrations (e.g. GitHub) are set.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// runSARIF analyzes the packages matching the patterns from args and writes the diagnostics to w as SARIF.
+// Each tag is a separate rule, e.g. musttag/json.
+func runSARIF(analyzer *analysis.Analyzer, args []string, w io.Writer) error {
+	diags, err := analyze(analyzer, args)
+	if err != nil {
+		return err
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "musttag",
+			Version:        version,
+			InformationURI: "https://github.com/go-simpler/musttag",
+			Rules:          []sarifRule{}, // empty lists rather than null.
+		}},
+		Results: []sarifResult{},
+	}
+
+	seen := make(map[string]bool)
+	for _, diag := range diags {
+		id := "musttag"
+		if diag.Tag != "" {
+			id += "/" + diag.Tag
+		}
+		if !seen[id] {
+			seen[id] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               id,
+				ShortDescription: sarifMessage{Text: ruleDescription(diag.Tag)},
+			})
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  id,
+			Level:   "warning",
+			Message: sarifMessage{Text: diag.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: diag.File},
+				Region:           sarifRegion{StartLine: diag.Line, StartColumn: diag.Col},
+			}}},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func ruleDescription(tag string) string {
+	if tag == "" {
+		return "Structs must be annotated with the tags of their encoders."
+	}
+	return "Structs must be annotated with the `" + tag + "` tag."
+}
@@ -0,0 +1,15 @@
+package app
+
+import (
+	"encoding/json"
+
+	"example.com/facts/events"
+)
+
+func handler(data []byte) {
+	event, err := events.Decode(data)
+	if err != nil {
+		return
+	}
+	json.Marshal(event)
+}
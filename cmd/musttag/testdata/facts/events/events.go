@@ -0,0 +1,17 @@
+package events
+
+import "encoding/json"
+
+type Event struct {
+	ID   string `json:"id"`
+	Kind string
+}
+
+// Decode returns the event behind an interface, so its struct is only known via facts.
+func Decode(data []byte) (any, error) {
+	var e Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
@@ -0,0 +1,19 @@
+package app
+
+import "encoding/json"
+
+type User struct {
+	Name  string `json:"name"`
+	Email string
+}
+
+type Order struct {
+	ID    string `json:"id"`
+	Total int    `json:"id"`
+}
+
+func handler() {
+	json.Marshal(User{})
+	json.Marshal(struct{ Token string }{})
+	json.Marshal(Order{})
+}
@@ -0,0 +1,11 @@
+package app
+
+import "encoding/json"
+
+type User struct {
+	Name string
+}
+
+func handler() {
+	json.Marshal(User{})
+}
@@ -0,0 +1,14 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type fixture struct {
+	Email string
+}
+
+func TestHandler(t *testing.T) {
+	json.Marshal(fixture{})
+}
@@ -0,0 +1,171 @@
+package musttag
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// resultFact marks a function whose first result is an interface but always holds the same named struct,
+// e.g. `func Decode([]byte) (any, error)` returning *User, so that its callers in other packages can check the struct.
+// The type is described by name, since types cannot be encoded.
+type resultFact struct {
+	PkgPath string // The package of the type.
+	Name    string // The name of the type.
+	Pointer bool   // Whether the result is a pointer to the type.
+}
+
+func (*resultFact) AFact() {}
+
+func (f *resultFact) String() string {
+	name := f.PkgPath + "." + f.Name
+	if f.Pointer {
+		return "returns *" + name
+	}
+	return "returns " + name
+}
+
+// exportResultFacts exports a [resultFact] for every function of the package known to return a concrete struct.
+func exportResultFacts(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.FuncDecl)
+			if !ok || decl.Body == nil {
+				continue
+			}
+			fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			if fact, ok := returnedStruct(pass, fn, decl.Body); ok {
+				pass.ExportObjectFact(fn, fact)
+			}
+		}
+	}
+}
+
+// returnedStruct returns the description of the named struct held by the first result of fn,
+// if it is an interface and every return statement of the body returns the same struct (or nil).
+func returnedStruct(pass *analysis.Pass, fn *types.Func, body *ast.BlockStmt) (*resultFact, bool) {
+	sig := fn.Type().(*types.Signature)
+	if sig.TypeParams().Len() > 0 || sig.Results().Len() == 0 || !types.IsInterface(sig.Results().At(0).Type()) {
+		return nil, false
+	}
+
+	var found types.Type
+	ok := true
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false // the returns of a closure are not the results of fn.
+		case *ast.ReturnStmt:
+			switch {
+			case len(node.Results) == sig.Results().Len():
+			case len(node.Results) == 1 && isCall(node.Results[0]): // e.g. `return f()`, the results of another function.
+			default:
+				ok = false // e.g. a bare return of named results.
+				return false
+			}
+			if isNil(pass.TypesInfo, node.Results[0]) {
+				return false // e.g. `return nil, err`.
+			}
+			typ := concreteType(pass, node.Results[0])
+			if typ == nil || (found != nil && !types.Identical(typ, found)) {
+				ok = false
+				return false
+			}
+			found = typ
+		}
+		return ok
+	})
+	if !ok || found == nil {
+		return nil, false
+	}
+
+	fact := new(resultFact)
+	if ptr, isPtr := types.Unalias(found).(*types.Pointer); isPtr {
+		fact.Pointer = true
+		found = ptr.Elem()
+	}
+	named, isNamed := types.Unalias(found).(*types.Named)
+	if !isNamed || named.Obj().Pkg() == nil || named.TypeArgs().Len() > 0 {
+		return nil, false // e.g. an anonymous struct, which cannot be described by name.
+	}
+	if _, isStruct := named.Underlying().(*types.Struct); !isStruct {
+		return nil, false
+	}
+	fact.PkgPath, fact.Name = named.Obj().Pkg().Path(), named.Obj().Name()
+	return fact, true
+}
+
+// concreteType returns the type of the expression, e.g. (the first result of) a call.
+// For an interface, the concrete type is returned if the expression is a call of a function with a [resultFact];
+// otherwise, nil is returned.
+func concreteType(pass *analysis.Pass, expr ast.Expr) types.Type {
+	typ := pass.TypesInfo.TypeOf(expr)
+	if tuple, ok := typ.(*types.Tuple); ok && tuple.Len() > 0 {
+		typ = tuple.At(0).Type() // e.g. (User, error).
+	}
+	if typ == nil {
+		return nil
+	}
+	if !types.IsInterface(typ) {
+		return typ
+	}
+
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil || callee.Pkg() == nil {
+		return nil
+	}
+	var fact resultFact
+	if !pass.ImportObjectFact(callee.Origin(), &fact) {
+		return nil
+	}
+
+	tn, ok := lookupPackage(callee.Pkg(), fact.PkgPath).Scope().Lookup(fact.Name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	if fact.Pointer {
+		return types.NewPointer(tn.Type())
+	}
+	return tn.Type()
+}
+
+// lookupPackage returns the package with the given path among pkg and its (transitive) imports.
+// If there is no such package, an empty one is returned.
+func lookupPackage(pkg *types.Package, path string) *types.Package {
+	seen := make(map[*types.Package]struct{})
+	queue := []*types.Package{pkg}
+	for len(queue) > 0 {
+		pkg, queue = queue[0], queue[1:]
+		if pkg.Path() == path {
+			return pkg
+		}
+		for _, imp := range pkg.Imports() {
+			if _, ok := seen[imp]; !ok {
+				seen[imp] = struct{}{}
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return types.NewPackage(path, "")
+}
+
+// isCall reports whether the expression is a call, e.g. `f()`.
+func isCall(expr ast.Expr) bool {
+	_, ok := ast.Unparen(expr).(*ast.CallExpr)
+	return ok
+}
+
+// isNil reports whether the expression is the untyped nil.
+func isNil(info *types.Info, expr ast.Expr) bool {
+	tv, ok := info.Types[expr]
+	return ok && tv.IsNil()
+}
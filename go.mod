@@ -5,6 +5,7 @@ go 1.22.0
 require (
 	go-simpler.org/assert v0.9.0
 	golang.org/x/tools v0.27.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
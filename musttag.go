@@ -2,11 +2,15 @@
 package musttag
 
 import (
+	"cmp"
 	"flag"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,207 +22,1703 @@ import (
 
 // Func describes a function call to look for, e.g. [json.Marshal].
 type Func struct {
-	Name   string // The full name of the function, including the package.
-	Tag    string // The struct tag whose presence should be ensured.
-	ArgPos int    // The position of the argument to check.
+	Name      string    // The full name of the function, including the package.
+	Tag       string    // The struct tag whose presence should be ensured.
+	ArgPos    int       // The position of the argument to check (not counting the receiver), or [ResultPos] to check the result.
+	Direction Direction // Whether the function encodes or decodes the argument (optional).
+	Message   string    // The message of the reports, instead of the default one (optional).
 
 	// a list of interface names (including the package);
 	// if at least one is implemented by the argument, no check is performed.
 	ifaceWhitelist []string
 }
 
+// ResultPos is a special [Func.ArgPos] to check the first result of the function instead of an argument,
+// e.g. User in `func Decode([]byte) (User, error)`.
+const ResultPos = -1
+
+// Direction describes whether a [Func] encodes or decodes its argument.
+type Direction int
+
+const (
+	Both   Direction = iota // The direction is unknown or the function does both; it is always checked.
+	Encode                  // The function encodes the argument, e.g. [json.Marshal].
+	Decode                  // The function decodes into the argument, e.g. [json.Unmarshal].
+)
+
+// Result is the result of the musttag analyzer; it lists everything that was reported.
+type Result struct {
+	Findings []Finding
+}
+
+// Finding describes a single report.
+type Finding struct {
+	Pos       token.Pos // The position of the report.
+	Type      string    // The type of the checked argument, relative to the analyzed package.
+	Field     string    // The name of the offending field.
+	Tag       string    // The struct tag being checked.
+	Direction Direction // The direction of the function the argument was passed to.
+	Message   string    // The message of the report.
+}
+
 // New creates a new musttag analyzer.
 // To report a custom function, provide its description as [Func].
+// The other options are the flags of the analyzer, the same as for the CLI,
+// e.g. `analyzer.Flags.Set("skip-types", "example.com/config.Duration")` to add a type to the default leaves.
 func New(funcs ...Func) *analysis.Analyzer {
-	var flagFuncs []Func
-	return &analysis.Analyzer{
-		Name:     "musttag",
-		Doc:      "enforce field tags in (un)marshaled structs",
-		Flags:    flags(&flagFuncs),
-		Requires: []*analysis.Analyzer{inspect.Analyzer},
+	// the functions passed here and via the -fn flag end up in the same per-analyzer config,
+	// so several analyzers with different options do not affect each other.
+	cfg := config{funcs: slices.Clone(funcs)}
+	analyzer := &analysis.Analyzer{
+		Name:       "musttag",
+		Doc:        "enforce field tags in (un)marshaled structs",
+		Flags:      flags(&cfg),
+		Requires:   []*analysis.Analyzer{inspect.Analyzer},
+		ResultType: reflect.TypeOf((*Result)(nil)),
+		// the code being edited (e.g. in gopls) is often incomplete, so check what can be checked.
+		RunDespiteErrors: true,
 		Run: func(pass *analysis.Pass) (any, error) {
-			l := len(builtins) + len(funcs) + len(flagFuncs)
+			l := len(builtins) + len(cfg.funcs)
 			allFuncs := make(map[string]Func, l)
 
 			merge := func(slice []Func) {
 				for _, fn := range slice {
-					allFuncs[fn.Name] = fn
+					allFuncs[funcName(fn.Name)] = fn
 				}
 			}
 			merge(builtins)
-			merge(funcs)
-			merge(flagFuncs)
+			merge(cfg.funcs)
+
+			// most packages never call a recognized function, so do not pay for the walk (and for `go mod edit`).
+			if !usesFuncs(pass.Pkg, allFuncs) {
+				return new(Result), nil
+			}
+
+			// the facts are needed by the importers, even if this package calls none of the functions itself.
+			if cfg.hasFacts() {
+				exportResultFacts(pass)
+			}
 
 			mainModule, err := getMainModule()
 			if err != nil {
 				return nil, err
 			}
 
-			return run(pass, mainModule, allFuncs)
+			// the dependencies (e.g. encoding/json itself) are only analyzed to export the facts,
+			// since their structs cannot be fixed anyway; yet a bad Func.ArgPos is an error in any package.
+			if !strings.HasPrefix(pass.Pkg.Path(), mainModule) {
+				return new(Result), checkArgPos(pass, allFuncs)
+			}
+
+			return run(pass, mainModule, allFuncs, &cfg)
 		},
 	}
+
+	// the facts require analyzing every dependency, so they are only declared if a function returns a struct.
+	cfg.factTypes = &analyzer.FactTypes
+	if slices.ContainsFunc(cfg.funcs, func(fn Func) bool { return fn.ArgPos == ResultPos }) {
+		cfg.enableFacts()
+	}
+	return analyzer
+}
+
+// categoryImported is the category of diagnostics about structs declared in other packages;
+// it can be used to treat them differently, e.g. with a lower severity.
+const categoryImported = "musttag/imported"
+
+// categoryChecked is the category of informational diagnostics listing the checked structs (see -list-checked).
+const categoryChecked = "musttag/checked"
+
+// config holds the options of a single analyzer, set via [New] and flags.
+type config struct {
+	funcs        []Func
+	direction    Direction
+	uniqueNames  bool
+	leavesOnly   bool
+	acceptTags   map[string][]string // primary tag -> fallback tags.
+	checkIfaces  bool
+	crossTags    []string
+	sharedTags   []string            // the tags required together, e.g. on a config struct shared by several encoders.
+	skipTypes    map[string]struct{} // in addition to defaultSkipTypes, unless noSkipTypes is set.
+	noSkipTypes  bool
+	ignoreTypes  map[string]struct{} // in addition to defaultIgnoreTypes.
+	verbose      bool
+	onlyTags     []string
+	strictTags   []string // the tags of leavesOnlyTags to check in the default mode.
+	emptyTags    bool
+	option       string // the option required on all tagged fields, e.g. omitempty.
+	omitempty    bool   // report the omitempty option on struct fields, which encoding/json ignores.
+	listChecked  bool
+	tagCases     map[string]CaseStyle // in addition to defaultTagCases.
+	tagRenames   map[string]string    // the tag of a Func -> the tag to check instead.
+	maxDepth     int                  // negative means unlimited.
+	perField     bool
+	singleImpl   bool
+	mapKeys      bool
+	requireAny   bool
+	anyKey       bool
+	countOnly    bool
+	envPrefix    bool
+	embeddedExt  bool // check the embedded structs declared outside of the main module.
+	reportAtCall bool // report the problems with fields at the call instead of the declaration.
+
+	factTypes *[]analysis.Fact // the FactTypes of the analyzer, see enableFacts.
+}
+
+// enableFacts declares [resultFact], so that the structs returned by the functions of other packages are known.
+// It makes the driver analyze every dependency from source, so it is only done for a [Func] with [ResultPos].
+func (cfg *config) enableFacts() {
+	if cfg.factTypes != nil && len(*cfg.factTypes) == 0 {
+		*cfg.factTypes = []analysis.Fact{new(resultFact)}
+	}
+}
+
+// hasFacts reports whether [resultFact] is declared, see enableFacts.
+func (cfg *config) hasFacts() bool {
+	return cfg.factTypes != nil && len(*cfg.factTypes) > 0
+}
+
+// defaultTagCases are the naming conventions of tag names used by suggested fixes;
+// the tags not listed here use snake_case.
+var defaultTagCases = map[string]CaseStyle{
+	"xml":    PascalCase,
+	"header": KebabCase,
+	"bson":   LowerCase,
+}
+
+// leavesOnlyTags are the tags whose nested structs only group other fields, so they are checked in the leaves-only mode,
+// e.g. CSV rows are flat and the nested keys of mapstructure (viper) configs are joined with a delimiter.
+var leavesOnlyTags = []string{"csv", "env", "mapstructure"}
+
+// scannerTags are the tags of database libraries, whose values of sql.Scanner and driver.Valuer types
+// are read from (or written to) a single column, so they are leaves, e.g. sql.NullString.
+var scannerTags = []string{"db", "bun", "pg", "gorm"}
+
+// scannerIfaces are the interfaces of the values stored in a single column, see [scannerTags].
+var scannerIfaces = []string{"database/sql.Scanner", "database/sql/driver.Valuer"}
+
+// ifaceWhitelistFor returns the interfaces skipped by the builtin functions of the tag in the direction,
+// e.g. encoding/xml.Marshaler for a struct marshaled with json and shared with xml, see the -shared-tags flag.
+func ifaceWhitelistFor(tag string, dir Direction) []string {
+	var whitelist []string
+	for _, fn := range builtins {
+		if fn.Tag != tag || (dir != Both && fn.Direction != Both && fn.Direction != dir) {
+			continue
+		}
+		for _, iface := range fn.ifaceWhitelist {
+			if !slices.Contains(whitelist, iface) {
+				whitelist = append(whitelist, iface)
+			}
+		}
+	}
+	return whitelist
+}
+
+// tagCase returns the naming convention of the tag names.
+func (cfg *config) tagCase(tag string) CaseStyle {
+	if style, ok := cfg.tagCases[tag]; ok {
+		return style
+	}
+	if style, ok := defaultTagCases[tag]; ok {
+		return style
+	}
+	return SnakeCase
+}
+
+// defaultIgnoreTypes are well-known types that cannot be (un)marshaled meaningfully,
+// yet often appear as exported fields of config and service structs.
+var defaultIgnoreTypes = []string{
+	"context.Context",
+	"io.Reader",
+	"io.Writer",
+	"database/sql.DB",
+	"net/http.Client",
+}
+
+// defaultSkipTypes are well-known types that are (un)marshaled as a whole, like json.RawMessage, or have no data at all, like sync.Mutex;
+// they are leaves, but the fields of such types still require tags.
+// It matters for the structs of other modules, which are only checked when embedded (see -check-embedded-imported).
+var defaultSkipTypes = []string{
+	"encoding/json.RawMessage",
+	"math/big.Int",
+	"net.IP",
+	"net/url.URL",
+	"sync.Mutex",
+	"sync.RWMutex",
+	"time.Duration",
+	"time.Time",
+}
+
+// defaultTagSkipTypes are the leaves of a single tag, in addition to defaultSkipTypes,
+// e.g. yaml.Node holds a raw yaml document, but is a plain struct for other encoders.
+var defaultTagSkipTypes = map[string][]string{
+	"yaml": {"gopkg.in/yaml.v3.Node"},
+}
+
+// skipTypesFor returns the leaves of the tag: the defaults (unless disabled) and the ones from the -skip-types flag.
+func (cfg *config) skipTypesFor(tag string) map[string]struct{} {
+	skipTypes := make(map[string]struct{}, len(defaultSkipTypes)+len(defaultTagSkipTypes[tag])+len(cfg.skipTypes))
+	if !cfg.noSkipTypes {
+		for _, name := range defaultSkipTypes {
+			skipTypes[name] = struct{}{}
+		}
+		for _, name := range defaultTagSkipTypes[tag] {
+			skipTypes[name] = struct{}{}
+		}
+	}
+	for name := range cfg.skipTypes {
+		skipTypes[name] = struct{}{}
+	}
+	return skipTypes
 }
 
-func flags(funcs *[]Func) flag.FlagSet {
+func flags(cfg *config) flag.FlagSet {
 	fs := flag.NewFlagSet("musttag", flag.ContinueOnError)
-	fs.Func("fn", "report a custom function (name:tag:arg-pos)", func(s string) error {
-		parts := strings.Split(s, ":")
-		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+	fs.Func("fn", "report a custom function (name:tag:arg-pos[:message]), use ret as arg-pos to check the result", func(s string) error {
+		parts := strings.SplitN(s, ":", 4)
+		if len(parts) < 3 || parts[0] == "" || parts[1] == "" {
 			return strconv.ErrSyntax
 		}
-		pos, err := strconv.Atoi(parts[2])
-		if err != nil {
-			return err
+		pos := ResultPos
+		if parts[2] != "ret" {
+			var err error
+			if pos, err = strconv.Atoi(parts[2]); err != nil {
+				return err
+			}
 		}
-		*funcs = append(*funcs, Func{
+		fn := Func{
 			Name:   parts[0],
 			Tag:    parts[1],
 			ArgPos: pos,
-		})
+		}
+		if len(parts) == 4 {
+			fn.Message = parts[3]
+		}
+		cfg.funcs = append(cfg.funcs, fn)
+		if fn.ArgPos == ResultPos {
+			cfg.enableFacts()
+		}
+		return nil
+	})
+	fs.Func("direction", "check only functions of the given direction (both|encode|decode)", func(s string) error {
+		switch s {
+		case "both":
+			cfg.direction = Both
+		case "encode":
+			cfg.direction = Encode
+		case "decode":
+			cfg.direction = Decode
+		default:
+			return strconv.ErrSyntax
+		}
 		return nil
 	})
+	fs.BoolVar(&cfg.uniqueNames, "unique-names", false, "report fields with duplicate tag names")
+	fs.Func("accept-tags", "accept fallback tags instead of the primary one (primary,fallback...)", func(s string) error {
+		tags := strings.Split(s, ",")
+		if len(tags) < 2 || slices.Contains(tags, "") {
+			return strconv.ErrSyntax
+		}
+		if cfg.acceptTags == nil {
+			cfg.acceptTags = make(map[string][]string)
+		}
+		cfg.acceptTags[tags[0]] = append(cfg.acceptTags[tags[0]], tags[1:]...)
+		return nil
+	})
+	fs.BoolVar(&cfg.checkIfaces, "check-interfaces", false, "require tags on fields of interface types")
+	fs.Func("cross-tag-consistency", "report fields whose names differ between the given tags (tag,tag...)", func(s string) error {
+		tags := strings.Split(s, ",")
+		if len(tags) < 2 || slices.Contains(tags, "") {
+			return strconv.ErrSyntax
+		}
+		cfg.crossTags = tags
+		return nil
+	})
+	fs.Func("shared-tags", "require all the given tags on structs checked for any of them (tag,tag...)", func(s string) error {
+		tags := strings.Split(s, ",")
+		if len(tags) < 2 || slices.Contains(tags, "") {
+			return strconv.ErrSyntax
+		}
+		cfg.sharedTags = tags
+		return nil
+	})
+	fs.Func("skip-types", "do not check the fields of the given types, in addition to the defaults (pkg.Type,...)", func(s string) error {
+		if cfg.skipTypes == nil {
+			cfg.skipTypes = make(map[string]struct{})
+		}
+		for _, name := range strings.Split(s, ",") {
+			if !strings.Contains(name, ".") {
+				return strconv.ErrSyntax
+			}
+			cfg.skipTypes[name] = struct{}{}
+		}
+		return nil
+	})
+	fs.BoolVar(&cfg.noSkipTypes, "no-default-skip-types", false, "check the fields of the well-known types skipped by default")
+	fs.Func("ignore-types", "do not check the fields of the given types at all, in addition to the defaults (pkg.Type,...)", func(s string) error {
+		if cfg.ignoreTypes == nil {
+			cfg.ignoreTypes = make(map[string]struct{})
+		}
+		for _, name := range strings.Split(s, ",") {
+			if !strings.Contains(name, ".") {
+				return strconv.ErrSyntax
+			}
+			cfg.ignoreTypes[name] = struct{}{}
+		}
+		return nil
+	})
+	fs.Func("only-tags", "report only the given tags (tag,...)", func(s string) error {
+		tags := strings.Split(s, ",")
+		if slices.Contains(tags, "") {
+			return strconv.ErrSyntax
+		}
+		cfg.onlyTags = append(cfg.onlyTags, tags...)
+		return nil
+	})
+	fs.Func("strict-tags", "require tags on nested struct fields of the tags checked in the leaves-only mode by default (tag,...)", func(s string) error {
+		tags := strings.Split(s, ",")
+		if slices.Contains(tags, "") {
+			return strconv.ErrSyntax
+		}
+		cfg.strictTags = append(cfg.strictTags, tags...)
+		return nil
+	})
+	fs.BoolVar(&cfg.verbose, "verbose-reports", false, "include the function that triggered the check in reports")
+	fs.BoolVar(&cfg.emptyTags, "flag-empty-tag", false, "report fields whose tag has an empty value")
+	fs.StringVar(&cfg.option, "require-option", "", "report tagged fields without the given option, e.g. omitempty")
+	fs.BoolVar(&cfg.omitempty, "warn-ineffective-omitempty", false, "report the json omitempty option on fields of struct types, which is ignored")
+	fs.BoolVar(&cfg.listChecked, "list-checked", false, "report every checked struct with the musttag/checked category")
+	fs.Func("tag-case", "the naming convention of the tag names in suggested fixes (tag:snake|camel|pascal|kebab|lower)", func(s string) error {
+		tag, name, ok := strings.Cut(s, ":")
+		style, known := caseStyles[name]
+		if !ok || tag == "" || !known {
+			return strconv.ErrSyntax
+		}
+		if cfg.tagCases == nil {
+			cfg.tagCases = make(map[string]CaseStyle)
+		}
+		cfg.tagCases[tag] = style
+		return nil
+	})
+	fs.Func("tag-rename", "check another tag instead of the tag of the functions (tag:renamed)", func(s string) error {
+		tag, renamed, ok := strings.Cut(s, ":")
+		if !ok || tag == "" || renamed == "" {
+			return strconv.ErrSyntax
+		}
+		if cfg.tagRenames == nil {
+			cfg.tagRenames = make(map[string]string)
+		}
+		cfg.tagRenames[tag] = renamed
+		return nil
+	})
+	fs.Func("report-at", "where to report the problems with fields (type|call)", func(s string) error {
+		switch s {
+		case "type":
+			cfg.reportAtCall = false
+		case "call":
+			cfg.reportAtCall = true
+		default:
+			return strconv.ErrSyntax
+		}
+		return nil
+	})
+	fs.IntVar(&cfg.maxDepth, "max-depth", -1, "do not check nested structs deeper than the given level (0 means only the top-level fields)")
+	fs.BoolVar(&cfg.perField, "per-field", false, "report every field missing the tag at its declaration, including the fields of nested structs")
+	fs.BoolVar(&cfg.singleImpl, "resolve-single-impl", false, "check the only struct of the package implementing the interface of an argument")
+	fs.BoolVar(&cfg.mapKeys, "check-map-keys", false, "check the struct keys of maps as well, e.g. for bson")
+	fs.BoolVar(&cfg.requireAny, "require-any", false, "report only structs without any tagged field")
+	fs.BoolVar(&cfg.anyKey, "any-key", false, "accept fields annotated with any tag, not only the one of the function")
+	fs.BoolVar(&cfg.countOnly, "count-only", false, "report only the number of fields missing tags per package")
+	fs.BoolVar(&cfg.envPrefix, "require-env-prefix", false, "require the envPrefix tag on nested struct fields checked for the env tag")
+	fs.BoolVar(&cfg.embeddedExt, "check-embedded-imported", false, "check the fields of embedded structs declared in other modules")
+	fs.BoolVar(&cfg.leavesOnly, "leaves-only", false, "do not require tags on fields of nested struct types")
 	return *fs
 }
 
-func run(pass *analysis.Pass, mainModule string, funcs map[string]Func) (_ any, err error) {
+func run(pass *analysis.Pass, mainModule string, funcs map[string]Func, cfg *config) (_ *Result, err error) {
 	visit := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 	filter := []ast.Node{(*ast.CallExpr)(nil)}
+	reportedFields := make(map[fieldReportKey]int) // the index into fieldDiags.
+	listedTypes := make(map[fieldReportKey]struct{})
 
-	visit.Preorder(filter, func(node ast.Node) {
+	ignoreTypes := make(map[string]struct{}, len(defaultIgnoreTypes)+len(cfg.ignoreTypes))
+	for _, name := range defaultIgnoreTypes {
+		ignoreTypes[name] = struct{}{}
+	}
+	for name := range cfg.ignoreTypes {
+		ignoreTypes[name] = struct{}{}
+	}
+	var fieldDiags []pendingReport
+
+	// the reports are collected and sorted by position, so the output is stable
+	// no matter the order in which the structs and their fields were checked.
+	var reports []pendingReport
+
+	visit.WithStack(filter, func(node ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
 		if err != nil {
-			return // there is already an error.
+			return true // there is already an error.
 		}
 
 		call, ok := node.(*ast.CallExpr)
 		if !ok {
-			return
+			return true
 		}
 
 		callee := typeutil.StaticCallee(pass.TypesInfo, call)
 		if callee == nil {
-			return
+			// a local variable holding a known function, e.g. `marshal := json.Marshal; marshal(v)`.
+			if value := assignedValue(pass.TypesInfo, stack, call.Fun); value != nil {
+				callee = funcValue(pass.TypesInfo, value)
+			}
+		}
+		if callee == nil {
+			return true
 		}
 
-		fn, ok := funcs[cutVendor(callee.FullName())]
+		fn, ok := funcs[funcName(callee.Origin().FullName())]
 		if !ok {
-			return
+			return true
 		}
 
-		if len(call.Args) <= fn.ArgPos {
-			err = fmt.Errorf("musttag: Func.ArgPos cannot be %d: %s accepts only %d argument(s)", fn.ArgPos, fn.Name, len(call.Args))
-			return
+		// a compatible encoder may read another tag, e.g. j instead of json.
+		if renamed, ok := cfg.tagRenames[fn.Tag]; ok {
+			fn.Tag = renamed
 		}
 
-		arg := call.Args[fn.ArgPos]
-		if ident, ok := arg.(*ast.Ident); ok && ident.Obj == nil {
-			return // e.g. json.Marshal(nil)
+		if cfg.direction != Both && fn.Direction != Both && fn.Direction != cfg.direction {
+			return true // the function is excluded by the -direction flag.
 		}
 
-		typ := pass.TypesInfo.TypeOf(arg)
-		if typ == nil {
-			return
+		// a struct shared by several encoders must be annotated with all their tags,
+		// e.g. a config struct served as json and read from a yaml or toml file.
+		tags := []string{fn.Tag}
+		if slices.Contains(cfg.sharedTags, fn.Tag) {
+			tags = cfg.sharedTags
+		}
+		if len(cfg.onlyTags) > 0 {
+			tags = slices.DeleteFunc(slices.Clone(tags), func(tag string) bool {
+				return !slices.Contains(cfg.onlyTags, tag)
+			})
+		}
+		if len(tags) == 0 {
+			return true // the tags are excluded by the -only-tags flag.
 		}
 
-		checker := checker{
-			mainModule:     mainModule,
-			seenTypes:      make(map[string]struct{}),
-			ifaceWhitelist: fn.ifaceWhitelist,
-			imports:        pass.Pkg.Imports(),
+		sig, ok := callee.Type().(*types.Signature)
+		if !ok {
+			return true
 		}
-		if checker.isValidType(typ, fn.Tag) {
-			return
+
+		if err = argPosError(fn, sig); err != nil {
+			return true
 		}
 
-		pass.Reportf(arg.Pos(), "the given struct should be annotated with the `%s` tag", fn.Tag)
-	})
+		var args []ast.Expr
+		if fn.ArgPos == ResultPos {
+			args = []ast.Expr{call} // the call itself is the decoded struct.
+		} else {
+			// Func.ArgPos does not count the receiver, unless it is passed explicitly,
+			// e.g. (*json.Encoder).Encode(enc, v) for a method expression.
+			argPos := fn.ArgPos
+			if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+				if s, ok := pass.TypesInfo.Selections[sel]; ok && s.Kind() == types.MethodExpr {
+					argPos++
+				}
+			}
 
-	return nil, err
-}
+			if len(call.Args) <= argPos {
+				return true // e.g. json.Marshal() while the code is being edited.
+			}
 
-type checker struct {
-	mainModule     string
-	seenTypes      map[string]struct{}
-	ifaceWhitelist []string
-	imports        []*types.Package
-}
+			args = call.Args[argPos : argPos+1]
+			if sig.Variadic() && fn.ArgPos == sig.Params().Len()-1 && !call.Ellipsis.IsValid() {
+				args = call.Args[argPos:] // all the variadic arguments, e.g. rdb.HSet(ctx, key, a, b).
+			}
+		}
 
-func (c *checker) isValidType(typ types.Type, tag string) bool {
-	if _, ok := c.seenTypes[typ.String()]; ok {
-		return true
-	}
-	c.seenTypes[typ.String()] = struct{}{}
+		callSite := analysis.RelatedInformation{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: "triggered by " + fn.Name,
+		}
 
-	styp, ok := c.parseStruct(typ)
-	if !ok {
-		return true
-	}
+		// verbose adds the trigger to the message; the call site itself is only related information.
+		verbose := func(diag *analysis.Diagnostic) {
+			posn := pass.Fset.Position(call.Pos())
+			diag.Message += fmt.Sprintf(" (triggered by %s at %s:%d)", fn.Name, filepath.Base(posn.Filename), posn.Line)
+		}
 
-	return c.isValidStruct(styp, tag)
-}
+		report := func(fn Func, r pendingReport) {
+			if cfg.verbose {
+				verbose(&r.diag)
+				r.diag.Related = append(r.diag.Related, callSite)
+			}
+			r.fn = fn
+			reports = append(reports, r)
+		}
 
-func (c *checker) parseStruct(typ types.Type) (*types.Struct, bool) {
-	if implementsInterface(typ, c.ifaceWhitelist, c.imports) {
-		return nil, false
-	}
+		checkArg := func(fn Func, arg ast.Expr, typ types.Type) {
+			ifaceWhitelist := fn.ifaceWhitelist
+			if slices.Contains(scannerTags, fn.Tag) {
+				ifaceWhitelist = append(slices.Clip(ifaceWhitelist), scannerIfaces...)
+			}
 
-	switch typ := typ.(type) {
-	case *types.Pointer:
-		return c.parseStruct(typ.Elem())
-	case *types.Array:
-		return c.parseStruct(typ.Elem())
-	case *types.Slice:
-		return c.parseStruct(typ.Elem())
-	case *types.Map:
-		return c.parseStruct(typ.Elem())
+			checker := checker{
+				mainModule:     mainModule,
+				owners:         make(map[*types.Var]*types.Struct),
+				seenTypes:      make(map[string]struct{}),
+				ifaceWhitelist: ifaceWhitelist,
+				imports:        pass.Pkg.Imports(),
+				uniqueNames:    cfg.uniqueNames,
+				leavesOnly:     cfg.leavesOnly || (slices.Contains(leavesOnlyTags, fn.Tag) && !slices.Contains(cfg.strictTags, fn.Tag)),
+				fallbackTags:   cfg.acceptTags[fn.Tag],
+				checkIfaces:    cfg.checkIfaces,
+				crossTags:      cfg.crossTags,
+				skipTypes:      cfg.skipTypesFor(fn.Tag),
+				ignoreTypes:    ignoreTypes,
+				emptyTags:      cfg.emptyTags,
+				option:         cfg.option,
+				omitempty:      cfg.omitempty,
+				listChecked:    cfg.listChecked,
+				maxDepth:       cfg.maxDepth,
+				perField:       cfg.perField || cfg.countOnly, // every missing field is counted, not only the first one.
+				mapKeys:        cfg.mapKeys,
+				requireAny:     cfg.requireAny,
+				anyKey:         cfg.anyKey,
+				envPrefix:      cfg.envPrefix,
+				embeddedExt:    cfg.embeddedExt,
+			}
+			field := checker.checkType(typ, fn.Tag)
 
-	case *types.Named: // a struct of the named type.
-		pkg := typ.Obj().Pkg()
-		if pkg == nil {
-			return nil, false
-		}
-		if !strings.HasPrefix(pkg.Path(), c.mainModule) {
-			return nil, false
+			for _, f := range checker.missing {
+				message := fmt.Sprintf("the field %s should be annotated with the `%s` tag", f.Name(), fn.Tag)
+				if fn.Message != "" {
+					message = fmt.Sprintf("%s (missing on field %s)", fn.Message, f.Name())
+				}
+				if f.Pkg() != pass.Pkg {
+					if cfg.countOnly {
+						// nothing is reported in the count-only mode, yet every field of an imported struct is counted.
+						diag := analysis.Diagnostic{Pos: arg.Pos(), Message: message, Category: categoryImported}
+						report(fn, pendingReport{diag: diag, field: f, owner: checker.owners[f], typ: typ})
+						continue
+					}
+					if field == nil {
+						field = f // the fields of imported structs cannot be reported at their declaration.
+					}
+					continue
+				}
+				checker.fieldReports = append(checker.fieldReports, fieldReport{
+					field:   f,
+					message: message,
+					fixable: true,
+				})
+			}
+
+			for _, t := range checker.checked {
+				diag := analysis.Diagnostic{Pos: arg.Pos(), Category: categoryChecked}
+				name := "an anonymous struct"
+				if named, ok := structType(t).(*types.Named); ok {
+					name = types.TypeString(named, types.RelativeTo(pass.Pkg))
+					if named.Obj().Pkg() == pass.Pkg {
+						diag.Pos = named.Obj().Pos()
+					}
+				}
+				diag.Message = fmt.Sprintf("checked %s for the `%s` tag", name, fn.Tag)
+				key := fieldReportKey{diag.Pos, diag.Message}
+				if _, ok := listedTypes[key]; ok {
+					continue
+				}
+				listedTypes[key] = struct{}{}
+				reports = append(reports, pendingReport{diag: diag, fn: fn, typ: t}) // not a finding.
+			}
+
+			for _, fr := range checker.fieldReports {
+				if cfg.reportAtCall {
+					// the developer is at the call, so the declaration of the field is only related information.
+					diag := analysis.Diagnostic{
+						Pos:     arg.Pos(),
+						Message: fmt.Sprintf("%s (in %s)", fr.message, types.TypeString(typ, types.RelativeTo(pass.Pkg))),
+						Related: []analysis.RelatedInformation{{Pos: fr.field.Pos(), Message: "the field is declared here"}},
+					}
+					if fr.field.Pkg() != pass.Pkg {
+						diag.Category = categoryImported
+					} else if fr.fixable {
+						if fix, ok := addTagFix(pass.Files, fr.field, fn.Tag, cfg.tagCase(fn.Tag)); ok {
+							diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+						}
+					}
+					r := pendingReport{diag: diag, field: fr.field, typ: typ}
+					if fr.fixable {
+						r.owner = checker.owners[fr.field]
+					}
+					report(fn, r)
+					continue
+				}
+
+				if fr.field.Pkg() != pass.Pkg {
+					continue // the struct is declared in another package.
+				}
+				// the report is anchored at the field, which may be far from the call,
+				// so point at every call site that made the struct checked.
+				key := fieldReportKey{fr.field.Pos(), fr.message}
+				if i, ok := reportedFields[key]; ok {
+					fieldDiags[i].diag.Related = append(fieldDiags[i].diag.Related, callSite)
+					continue
+				}
+				reportedFields[key] = len(fieldDiags)
+				diag := analysis.Diagnostic{
+					Pos:     fr.field.Pos(),
+					Message: fr.message,
+					Related: []analysis.RelatedInformation{callSite},
+				}
+				if cfg.verbose {
+					verbose(&diag)
+				}
+				if fr.fixable {
+					if fix, ok := addTagFix(pass.Files, fr.field, fn.Tag, cfg.tagCase(fn.Tag)); ok {
+						diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+					}
+				}
+				r := pendingReport{diag: diag, field: fr.field, fn: fn, typ: typ}
+				if fr.fixable {
+					r.owner = checker.owners[fr.field]
+				}
+				fieldDiags = append(fieldDiags, r)
+			}
+
+			if field == nil {
+				return
+			}
+
+			diag := analysis.Diagnostic{
+				Pos:     arg.Pos(),
+				Message: fmt.Sprintf("the given struct should be annotated with the `%s` tag", fn.Tag),
+			}
+
+			// anonymous structs have no declaration to look at, so give some context instead.
+			if isAnonymousStruct(typ) {
+				diag.Pos = elementPos(arg)
+				if name := enclosingFuncName(stack); name != "" {
+					owner := "the anonymous struct in " + name
+					if named, ok := checker.names[checker.owners[field]]; ok {
+						owner = types.TypeString(named, types.RelativeTo(pass.Pkg)) // a nested named struct, e.g. struct{ Bar Bar }.
+					}
+					diag.Message += fmt.Sprintf(" (missing on field %q of %s)", field.Name(), owner)
+				}
+			}
+			if fn.Message != "" {
+				diag.Message = fn.Message
+			}
+
+			if field.Pkg() != pass.Pkg {
+				diag.Category = categoryImported
+			} else if fix, ok := addTagFix(pass.Files, field, fn.Tag, cfg.tagCase(fn.Tag)); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+			}
+
+			report(fn, pendingReport{diag: diag, field: field, owner: checker.owners[field], typ: typ})
 		}
-		styp, ok := typ.Underlying().(*types.Struct)
-		if !ok {
-			return nil, false
+
+		for _, tag := range tags {
+			f := fn
+			if tag != fn.Tag {
+				// the interfaces of the function only skip its own tag, e.g. encoding/json.Marshaler does not affect xml.
+				f.Tag, f.ifaceWhitelist = tag, ifaceWhitelistFor(tag, fn.Direction)
+			}
+			for _, arg := range args {
+				if ident, ok := arg.(*ast.Ident); ok && ident.Obj == nil {
+					continue // e.g. json.Marshal(nil)
+				}
+
+				typ := pass.TypesInfo.TypeOf(arg)
+				if tuple, ok := typ.(*types.Tuple); ok {
+					typ = tuple.At(0).Type() // the first result of a ResultPos function, e.g. (User, error).
+				}
+				if typ == nil {
+					continue
+				}
+
+				checkArg(f, arg, typ)
+
+				// the elements of an inline literal of interfaces have concrete types, e.g. []any{Foo{}, Bar{}}.
+				for _, elt := range interfaceElements(pass.TypesInfo, arg) {
+					if typ := pass.TypesInfo.TypeOf(elt); typ != nil {
+						checkArg(f, elt, typ)
+					}
+				}
+
+				// a local variable of an interface type may hold a known concrete value, e.g. `var v any = &Foo{}`,
+				// and a function returning an interface may be known to return a concrete one, see resultFact.
+				if types.IsInterface(typ) {
+					value := arg
+					if v := assignedValue(pass.TypesInfo, stack, arg); v != nil {
+						value = v
+					}
+					if impl := concreteType(pass, value); impl != nil {
+						checkArg(f, arg, impl)
+					} else if cfg.singleImpl {
+						if impl := singleImplementer(pass.Pkg, typ); impl != nil {
+							checkArg(f, arg, impl)
+						}
+					}
+				}
+			}
 		}
-		return styp, true
 
-	case *types.Struct: // an anonymous struct.
-		return typ, true
+		return true
+	})
 
-	default:
-		return nil, false
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (c *checker) isValidStruct(styp *types.Struct, tag string) bool {
-	for i := 0; i < styp.NumFields(); i++ {
-		field := styp.Field(i)
-		if !field.Exported() {
-			continue
-		}
+	// the field reports are collected until all the call sites are known.
+	reports = append(reports, fieldDiags...)
 
-		tagValue, ok := reflect.StructTag(styp.Tag(i)).Lookup(tag)
-		if !ok {
-			// tag is not required for embedded types.
-			if !field.Embedded() {
-				return false
-			}
+	slices.SortStableFunc(reports, func(a, b pendingReport) int {
+		return cmp.Compare(a.diag.Pos, b.diag.Pos)
+	})
+
+	ignoredFiles := fileIgnoredFiles(pass.Files)
+
+	result := new(Result)
+	reported := make(map[fieldReportKey]struct{}, len(reports))
+	missingFields := make(map[*types.Var]struct{})
+	missingStructs := make(map[*types.Struct]struct{})
+	for _, r := range reports {
+		key := fieldReportKey{r.diag.Pos, r.diag.Message}
+		if _, ok := reported[key]; ok {
+			continue // e.g. the same argument checked via its assigned value.
 		}
+		reported[key] = struct{}{}
 
-		// the field is explicitly ignored.
-		if tagValue == "-" {
+		if slices.ContainsFunc(ignoredFiles, func(file *ast.File) bool {
+			return file.FileStart <= r.diag.Pos && r.diag.Pos < file.FileEnd
+		}) {
 			continue
 		}
 
-		if !c.isValidType(field.Type(), tag) {
-			return false
+		if r.field != nil {
+			result.Findings = append(result.Findings, Finding{
+				Pos:       r.diag.Pos,
+				Type:      types.TypeString(r.typ, types.RelativeTo(pass.Pkg)),
+				Field:     r.field.Name(),
+				Tag:       r.fn.Tag,
+				Direction: r.fn.Direction,
+				Message:   r.diag.Message,
+			})
 		}
+		if r.owner != nil {
+			missingFields[r.field] = struct{}{}
+			missingStructs[r.owner] = struct{}{}
+		}
+		if !cfg.countOnly {
+			pass.Report(r.diag)
+		}
+	}
+
+	// a single summary instead of the individual reports, e.g. to measure the progress of a rollout.
+	if cfg.countOnly && len(missingFields) > 0 {
+		pass.Reportf(pass.Files[0].Package, "%d field(s) across %d struct(s) missing tags", len(missingFields), len(missingStructs))
 	}
 
-	return true
+	return result, nil
+}
+
+// fileIgnoreDirective suppresses all the reports in a file, e.g. a legacy one being migrated.
+// It must be placed above the package clause.
+const fileIgnoreDirective = "//musttag:file-ignore"
+
+// fileIgnoredFiles returns the files with the [fileIgnoreDirective].
+func fileIgnoredFiles(files []*ast.File) []*ast.File {
+	var ignored []*ast.File
+	for _, file := range files {
+		for _, group := range file.Comments {
+			if group.Pos() > file.Package {
+				break
+			}
+			if slices.ContainsFunc(group.List, func(c *ast.Comment) bool { return c.Text == fileIgnoreDirective }) {
+				ignored = append(ignored, file)
+				break
+			}
+		}
+	}
+	return ignored
+}
+
+// pendingReport is a report waiting to be sorted,
+// or a field report waiting for all its related call sites to be collected.
+// The field is nil for informational reports.
+type pendingReport struct {
+	diag  analysis.Diagnostic
+	field *types.Var
+	owner *types.Struct // The struct of the field missing the tag, nil for other reports.
+	fn    Func          // The function the argument was passed to.
+	typ   types.Type    // The type of the argument.
+}
+
+// argPosError returns an error if [Func.ArgPos] does not fit the signature of the function.
+func argPosError(fn Func, sig *types.Signature) error {
+	if fn.ArgPos == ResultPos {
+		if sig.Results().Len() == 0 {
+			return fmt.Errorf("musttag: Func.ArgPos cannot be ResultPos: %s returns nothing", fn.Name)
+		}
+		return nil
+	}
+	if params := sig.Params().Len(); fn.ArgPos < 0 || (fn.ArgPos >= params && !sig.Variadic()) {
+		return fmt.Errorf("musttag: Func.ArgPos cannot be %d: %s accepts only %d argument(s)", fn.ArgPos, fn.Name, params)
+	}
+	return nil
+}
+
+// checkArgPos returns an error if [Func.ArgPos] of a function called by the package does not fit its signature;
+// it is used for the packages whose calls are not checked otherwise, e.g. the dependencies analyzed for facts.
+func checkArgPos(pass *analysis.Pass, funcs map[string]Func) error {
+	visit := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	filter := []ast.Node{(*ast.CallExpr)(nil)}
+
+	var err error
+	visit.Preorder(filter, func(node ast.Node) {
+		if err != nil {
+			return // there is already an error.
+		}
+		callee := typeutil.StaticCallee(pass.TypesInfo, node.(*ast.CallExpr))
+		if callee == nil {
+			return
+		}
+		fn, ok := funcs[funcName(callee.Origin().FullName())]
+		if !ok {
+			return
+		}
+		if sig, ok := callee.Type().(*types.Signature); ok {
+			err = argPosError(fn, sig)
+		}
+	})
+	return err
+}
+
+// usesFuncs reports whether the package may call any of the functions, i.e. it imports (or is) the package of one.
+// It only looks at the imports, so it is much cheaper than looking at every call and skips most packages;
+// a method called via a value of a package that is not imported (e.g. a field of an imported struct) is missed.
+func usesFuncs(pkg *types.Package, funcs map[string]Func) bool {
+	pkgs := make(map[string]struct{}, len(funcs))
+	for name := range funcs {
+		pkgs[funcPkgPath(name)] = struct{}{}
+	}
+
+	has := func(pkg *types.Package) bool {
+		_, ok := pkgs[cutVendor(pkg.Path())]
+		return ok
+	}
+
+	if has(pkg) {
+		return true
+	}
+	for _, imp := range pkg.Imports() {
+		if has(imp) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type checker struct {
+	mainModule     string
+	seenTypes      map[string]struct{}
+	ifaceWhitelist []string
+	imports        []*types.Package
+	uniqueNames    bool
+	leavesOnly     bool
+	fallbackTags   []string
+	checkIfaces    bool
+	crossTags      []string
+	skipTypes      map[string]struct{}
+	ignoreTypes    map[string]struct{}
+	emptyTags      bool
+	option         string
+	omitempty      bool
+	listChecked    bool
+	checked        []types.Type // the types whose structs were checked, if listChecked.
+	maxDepth       int
+	depth          int // the nesting level of the struct being checked.
+	perField       bool
+	missing        []*types.Var                   // the fields missing the tag, if perField.
+	owners         map[*types.Var]*types.Struct   // the structs of the fields missing the tag.
+	names          map[*types.Struct]*types.Named // the named types of the checked structs.
+	mapKeys        bool
+	requireAny     bool
+	anyKey         bool
+	envPrefix      bool
+	embeddedExt    bool
+	external       bool // the struct being parsed may be declared outside of the main module.
+	fieldReports   []fieldReport
+}
+
+// fieldReport describes a problem with a field itself (rather than with the whole struct),
+// e.g. a duplicate tag name.
+type fieldReport struct {
+	field   *types.Var
+	message string
+	fixable bool // the field is missing the tag, so it can be added by a suggested fix.
+}
+
+type fieldReportKey struct {
+	pos     token.Pos
+	message string
+}
+
+// checkType returns the first exported field of typ (or of its nested types) not annotated with the tag.
+// If typ is valid, nil is returned.
+// In the per-field mode, nil is always returned, and all such fields are collected instead.
+func (c *checker) checkType(typ types.Type, tag string) *types.Var {
+	if _, ok := c.seenTypes[typ.String()]; ok {
+		return nil
+	}
+	c.seenTypes[typ.String()] = struct{}{}
+
+	// some encoders (e.g. bson) serialize the struct keys of maps with their fields.
+	if c.mapKeys {
+		if key := mapKey(typ); key != nil {
+			if missing := c.checkType(key, tag); missing != nil {
+				return missing
+			}
+		}
+	}
+
+	styp, ok := c.parseStruct(typ)
+	if !ok {
+		return nil
+	}
+	if named, ok := structType(typ).(*types.Named); ok {
+		if c.names == nil {
+			c.names = make(map[*types.Struct]*types.Named)
+		}
+		c.names[styp] = named
+	}
+	if c.listChecked {
+		c.checked = append(c.checked, typ)
+	}
+
+	return c.checkStruct(styp, tag)
+}
+
+func (c *checker) parseStruct(typ types.Type) (*types.Struct, bool) {
+	if typ == nil || typ == types.Typ[types.Invalid] {
+		return nil, false // the code does not compile, e.g. the type is undefined.
+	}
+	if implementsInterface(typ, c.ifaceWhitelist, c.imports) {
+		return nil, false
+	}
+
+	switch typ := types.Unalias(typ).(type) {
+	case *types.Pointer:
+		return c.parseStruct(typ.Elem())
+	case *types.Array:
+		return c.parseStruct(typ.Elem())
+	case *types.Slice:
+		return c.parseStruct(typ.Elem())
+	case *types.Map:
+		return c.parseStruct(typ.Elem())
+
+	case *types.Named: // a struct of the named type.
+		pkg := typ.Obj().Pkg()
+		if pkg == nil {
+			return nil, false
+		}
+		name := cutVendor(pkg.Path()) + "." + typ.Obj().Name()
+		if _, ok := c.skipTypes[name]; ok {
+			return nil, false // the type is a leaf, e.g. it is converted by a decoder hook.
+		}
+		if !strings.HasPrefix(pkg.Path(), c.mainModule) && !c.external {
+			return nil, false
+		}
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			if _, ok := structType(typ).Underlying().(*types.Pointer); ok {
+				return nil, false // a cycle of named pointers, e.g. `type P *P`.
+			}
+			return c.parseStruct(ptr) // a named pointer, e.g. `type FooPtr *Foo`.
+		}
+		styp, ok := typ.Underlying().(*types.Struct)
+		if !ok {
+			return nil, false
+		}
+		return styp, true
+
+	case *types.Struct: // an anonymous struct.
+		return typ, true
+
+	default:
+		return nil, false
+	}
+}
+
+func (c *checker) checkStruct(styp *types.Struct, tag string) *types.Var {
+	if isExempt(styp) {
+		return nil
+	}
+
+	if c.uniqueNames {
+		c.checkNames(styp, tag)
+	}
+	if len(c.crossTags) > 1 {
+		c.checkCrossTags(styp)
+	}
+
+	// in the require-any mode, a struct with at least one tagged field is considered annotated.
+	annotated := c.requireAny && c.hasTaggedField(styp, tag)
+
+	for i := 0; i < styp.NumFields(); i++ {
+		field := styp.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		// the field is never (un)marshaled meaningfully, e.g. context.Context.
+		if c.isIgnoredType(field.Type()) {
+			continue
+		}
+
+		tagValue, ok := c.lookupTag(styp.Tag(i), tag)
+		// in the any-key mode, a field annotated with any tag is considered annotated, e.g. `yaml:"name"` for json.
+		keyed := ok || (c.anyKey && hasAnyKey(styp.Tag(i)))
+		if !keyed && !annotated && c.requiresTag(field) {
+			c.owners[field] = styp
+			if !c.perField {
+				return field
+			}
+			// keep looking, the nested structs may miss the tag as well.
+			c.missing = append(c.missing, field)
+		}
+
+		// the variables of a nested struct are named after the envPrefix tag of its field, e.g. `envPrefix:"DB_"`.
+		if c.envPrefix && tag == "env" && !field.Embedded() && c.isNestedStruct(field.Type()) {
+			if _, ok := reflect.StructTag(styp.Tag(i)).Lookup("envPrefix"); !ok {
+				c.fieldReports = append(c.fieldReports, fieldReport{
+					field:   field,
+					message: fmt.Sprintf("the nested struct field %s should be annotated with the `envPrefix` tag", field.Name()),
+				})
+			}
+		}
+
+		// `json:""` means the same as no tag at all, yet looks intentional; it is usually a copy-paste error.
+		if ok && tagValue == "" && c.emptyTags {
+			c.fieldReports = append(c.fieldReports, fieldReport{
+				field:   field,
+				message: fmt.Sprintf("the `%s` tag of field %s is empty", tag, field.Name()),
+			})
+		}
+
+		// the field is explicitly ignored.
+		if tagValue == "-" {
+			continue
+		}
+
+		if ok && c.option != "" && !hasOption(tagValue, c.option) {
+			c.fieldReports = append(c.fieldReports, fieldReport{
+				field:   field,
+				message: fmt.Sprintf("the `%s` tag of field %s should have the %q option", tag, field.Name(), c.option),
+			})
+		}
+
+		// encoding/json never considers a struct empty, so the field is always encoded, unlike a pointer to it.
+		if ok && c.omitempty && tag == "json" && hasOption(tagValue, "omitempty") && isStructValue(field.Type()) {
+			c.fieldReports = append(c.fieldReports, fieldReport{
+				field:   field,
+				message: fmt.Sprintf("the \"omitempty\" option of field %s has no effect on a struct type", field.Name()),
+			})
+		}
+
+		if isLeafField(tag, tagValue) {
+			continue
+		}
+
+		if c.maxDepth >= 0 && c.depth >= c.maxDepth {
+			continue // the nested structs are too deep.
+		}
+
+		c.depth++
+		// the fields of an embedded struct are promoted, so they are (un)marshaled as the own fields.
+		c.external = c.embeddedExt && field.Embedded()
+		missing := c.checkType(field.Type(), tag)
+		c.external = false
+		c.depth--
+		if missing != nil {
+			return missing
+		}
+	}
+
+	return nil
+}
+
+// isExempt reports whether styp has a field with the `musttag:"-"` marker, e.g. a blank `_ struct{}` field.
+// Such structs are not checked at all.
+func isExempt(styp *types.Struct) bool {
+	for i := 0; i < styp.NumFields(); i++ {
+		if reflect.StructTag(styp.Tag(i)).Get("musttag") == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// requiresTag reports whether the field must be annotated with the tag.
+func (c *checker) requiresTag(field *types.Var) bool {
+	switch {
+	case field.Embedded():
+		return false // tag is not required for embedded types.
+	case c.leavesOnly && c.isNestedStruct(field.Type()):
+		return false // in the leaves-only mode, nested structs only group other fields.
+	case c.isSkippedIface(field.Type()):
+		return false
+	default:
+		return true
+	}
+}
+
+// hasTaggedField reports whether any exported field of styp has the tag (or a fallback one).
+func (c *checker) hasTaggedField(styp *types.Struct, tag string) bool {
+	for i := 0; i < styp.NumFields(); i++ {
+		if _, ok := c.lookupTag(styp.Tag(i), tag); ok && styp.Field(i).Exported() {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupTag returns the value of the tag, or of the first present fallback tag.
+func (c *checker) lookupTag(structTag, tag string) (string, bool) {
+	st := reflect.StructTag(structTag)
+	if value, ok := st.Lookup(tag); ok {
+		return value, true
+	}
+	for _, fallback := range c.fallbackTags {
+		if value, ok := st.Lookup(fallback); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// isSkippedIface reports whether typ is an interface (e.g. error) whose fields do not require tags.
+// Interfaces hold arbitrary values that cannot be described by a schema anyway.
+func (c *checker) isSkippedIface(typ types.Type) bool {
+	if c.checkIfaces {
+		return false
+	}
+	if _, ok := typ.(*types.TypeParam); ok {
+		return false
+	}
+	return types.IsInterface(typ)
+}
+
+// isIgnoredType reports whether typ (or the type it points to) is one of the ignored types.
+func (c *checker) isIgnoredType(typ types.Type) bool {
+	for {
+		ptr, ok := types.Unalias(typ).(*types.Pointer)
+		if !ok {
+			break
+		}
+		typ = ptr.Elem()
+	}
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false // e.g. error.
+	}
+	_, ok = c.ignoreTypes[cutVendor(named.Obj().Pkg().Path())+"."+named.Obj().Name()]
+	return ok
+}
+
+// isStructValue reports whether typ is a struct (not a pointer to it), e.g. time.Time.
+func isStructValue(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Struct)
+	return ok
+}
+
+// isNestedStruct reports whether typ is a struct (or a pointer to it) whose fields are checked as well.
+func (c *checker) isNestedStruct(typ types.Type) bool {
+	for {
+		ptr, ok := types.Unalias(typ).(*types.Pointer)
+		if !ok {
+			break
+		}
+		typ = ptr.Elem()
+	}
+	switch types.Unalias(typ).(type) {
+	case *types.Named, *types.Struct:
+		_, ok := c.parseStruct(typ)
+		return ok
+	default:
+		return false
+	}
+}
+
+// leafOptions are the options that make a field (un)marshaled as is, so its type is not checked deeper,
+// e.g. a catch-all map for the unused keys of `mapstructure:",remain"` or the raw text of `xml:",innerxml"`.
+var leafOptions = map[string][]string{
+	"mapstructure": {"remain"},
+	"xml":          {"chardata", "cdata", "innerxml", "comment"},
+}
+
+// isLeafField reports whether the tag value has one of the leaf options of the tag.
+func isLeafField(tag, tagValue string) bool {
+	for _, opt := range leafOptions[tag] {
+		if hasOption(tagValue, opt) {
+			return true
+		}
+	}
+	return false
+}
+
+// inlineOptions are the options that promote the fields of a nested struct to the parent level, e.g. `yaml:",inline"`.
+var inlineOptions = map[string]string{
+	"mapstructure": "squash",
+	"yaml":         "inline",
+}
+
+// checkNames collects the fields of styp whose tag names are already used by previous fields.
+// The fields of embedded structs without a tag name are promoted to the same level, just like encoding/json does.
+func (c *checker) checkNames(styp *types.Struct, tag string) {
+	seen := make(map[string]*types.Var)
+	visited := make(map[*types.Struct]struct{})
+
+	var walk func(styp *types.Struct)
+	walk = func(styp *types.Struct) {
+		if _, ok := visited[styp]; ok {
+			return
+		}
+		visited[styp] = struct{}{}
+
+		for i := 0; i < styp.NumFields(); i++ {
+			field := styp.Field(i)
+			if !field.Exported() {
+				continue
+			}
+
+			tagValue, _ := c.lookupTag(styp.Tag(i), tag)
+			if tagValue == "-" {
+				continue
+			}
+
+			name, _, _ := strings.Cut(tagValue, ",")
+			if opt, ok := inlineOptions[tag]; ok && hasOption(tagValue, opt) {
+				if inlined, ok := embeddedStruct(field.Type()); ok {
+					walk(inlined)
+					continue
+				}
+			}
+			if field.Embedded() && name == "" {
+				if embedded, ok := embeddedStruct(field.Type()); ok {
+					walk(embedded)
+					continue
+				}
+			}
+			if name == "" {
+				name = field.Name()
+			}
+
+			if first, ok := seen[name]; ok {
+				c.fieldReports = append(c.fieldReports, fieldReport{
+					field:   field,
+					message: fmt.Sprintf("the `%s` tag name %q of field %s is already used by field %s", tag, name, field.Name(), first.Name()),
+				})
+				continue
+			}
+			seen[name] = field
+		}
+	}
+
+	walk(styp)
+}
+
+// checkCrossTags collects the fields of styp whose names in the cross-checked tags differ, e.g. `json:"a" yaml:"b"`.
+// Only explicit names are compared, since the default ones depend on the encoder.
+func (c *checker) checkCrossTags(styp *types.Struct) {
+	for i := 0; i < styp.NumFields(); i++ {
+		field := styp.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		st := reflect.StructTag(styp.Tag(i))
+		var firstTag, firstName string
+		for _, tag := range c.crossTags {
+			value, ok := st.Lookup(tag)
+			if !ok || value == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(value, ",")
+			if name == "" {
+				continue
+			}
+			if firstTag == "" {
+				firstTag, firstName = tag, name
+				continue
+			}
+			if name != firstName {
+				c.fieldReports = append(c.fieldReports, fieldReport{
+					field:   field,
+					message: fmt.Sprintf("the `%s` tag name %q of field %s does not match the `%s` tag name %q", tag, name, field.Name(), firstTag, firstName),
+				})
+			}
+		}
+	}
+}
+
+// embeddedStruct returns the struct of an embedded field of type T or *T.
+func embeddedStruct(typ types.Type) (*types.Struct, bool) {
+	if ptr, ok := types.Unalias(typ).(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	styp, ok := typ.Underlying().(*types.Struct)
+	return styp, ok
+}
+
+// mapKey returns the key type of the map that typ refers to, e.g. Key for []map[Key]V.
+// If typ does not refer to a map, nil is returned.
+func mapKey(typ types.Type) types.Type {
+	for {
+		switch t := types.Unalias(typ).(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+		case *types.Array:
+			typ = t.Elem()
+		case *types.Slice:
+			typ = t.Elem()
+		case *types.Map:
+			return t.Key()
+		default:
+			return nil
+		}
+	}
+}
+
+// structType returns the named or anonymous struct type that typ refers to, e.g. Foo for []*Foo.
+// For a cycle of named pointers, e.g. `type P *P`, the named pointer itself is returned.
+func structType(typ types.Type) types.Type {
+	var seen map[*types.Named]struct{} // the named pointers, which may point to themselves, e.g. `type P *P`.
+	for {
+		switch t := types.Unalias(typ).(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+		case *types.Array:
+			typ = t.Elem()
+		case *types.Slice:
+			typ = t.Elem()
+		case *types.Map:
+			typ = t.Elem()
+		case *types.Named:
+			if ptr, ok := t.Underlying().(*types.Pointer); ok {
+				if _, ok := seen[t]; ok {
+					return t
+				}
+				if seen == nil {
+					seen = make(map[*types.Named]struct{})
+				}
+				seen[t] = struct{}{}
+				typ = ptr.Elem() // a named pointer, e.g. `type FooPtr *Foo`.
+				continue
+			}
+			return t
+		default:
+			return t
+		}
+	}
+}
+
+// addTagFix returns a fix that adds the tag to the declaration of the field, e.g. `json:"user_id"` to UserID.
+// If the declaration cannot be edited (e.g. `A, B string`), false is returned.
+func addTagFix(files []*ast.File, field *types.Var, tag string, style CaseStyle) (analysis.SuggestedFix, bool) {
+	decl := fieldDecl(files, field)
+	if decl == nil || len(decl.Names) != 1 {
+		return analysis.SuggestedFix{}, false
+	}
+
+	pair := fmt.Sprintf("%s:%q", tag, TagNameFor(field.Name(), style))
+	fix := analysis.SuggestedFix{Message: fmt.Sprintf("Add the `%s` tag to field %s", pair, field.Name())}
+
+	switch {
+	case decl.Tag == nil:
+		pos := decl.Type.End()
+		fix.TextEdits = []analysis.TextEdit{{Pos: pos, End: pos, NewText: []byte(" `" + pair + "`")}}
+	case strings.HasPrefix(decl.Tag.Value, "`"):
+		pos := decl.Tag.End() - 1 // before the closing backquote.
+		if decl.Tag.Value != "``" {
+			pair = " " + pair
+		}
+		fix.TextEdits = []analysis.TextEdit{{Pos: pos, End: pos, NewText: []byte(pair)}}
+	default:
+		return analysis.SuggestedFix{}, false // an interpreted string, e.g. "xml:\"name\"".
+	}
+
+	return fix, true
+}
+
+// fieldDecl returns the declaration of the field, or nil if it is not declared in the files.
+func fieldDecl(files []*ast.File, field *types.Var) *ast.Field {
+	for _, file := range files {
+		if field.Pos() < file.Pos() || field.Pos() >= file.End() {
+			continue
+		}
+		var decl *ast.Field
+		ast.Inspect(file, func(node ast.Node) bool {
+			if f, ok := node.(*ast.Field); ok {
+				for _, name := range f.Names {
+					if name.Pos() == field.Pos() {
+						decl = f
+					}
+				}
+			}
+			return decl == nil
+		})
+		return decl
+	}
+	return nil
+}
+
+// isAnonymousStruct reports whether typ is an anonymous struct,
+// possibly wrapped in pointers, arrays, slices or maps.
+func isAnonymousStruct(typ types.Type) bool {
+	switch typ := types.Unalias(typ).(type) {
+	case *types.Pointer:
+		return isAnonymousStruct(typ.Elem())
+	case *types.Array:
+		return isAnonymousStruct(typ.Elem())
+	case *types.Slice:
+		return isAnonymousStruct(typ.Elem())
+	case *types.Map:
+		return isAnonymousStruct(typ.Elem())
+	case *types.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+// funcValue returns the function of a function value, e.g. json.Marshal or enc.Encode (a bound method).
+// Method expressions, e.g. (*json.Encoder).Encode, are not supported, since they take the receiver as the first argument.
+func funcValue(info *types.Info, expr ast.Expr) *types.Func {
+	expr = ast.Unparen(expr)
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if s, ok := info.Selections[sel]; ok && s.Kind() == types.MethodExpr {
+			return nil
+		}
+	}
+	return typeutil.StaticCallee(info, &ast.CallExpr{Fun: expr})
+}
+
+// interfaceElements returns the elements of an inline slice, array or map literal of interfaces,
+// e.g. `Foo{}` and `Bar{}` in `[]any{Foo{}, Bar{}}`. Elements of interface types are omitted.
+func interfaceElements(info *types.Info, expr ast.Expr) []ast.Expr {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	composite, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+
+	typ := info.TypeOf(composite)
+	if typ == nil {
+		return nil
+	}
+
+	var elem types.Type
+	switch typ := typ.Underlying().(type) {
+	case *types.Slice:
+		elem = typ.Elem()
+	case *types.Array:
+		elem = typ.Elem()
+	case *types.Map:
+		elem = typ.Elem()
+	default:
+		return nil
+	}
+	if !types.IsInterface(elem) {
+		return nil
+	}
+
+	var elts []ast.Expr
+	for _, elt := range composite.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		if typ := info.TypeOf(elt); typ != nil && !types.IsInterface(typ) {
+			elts = append(elts, elt)
+		}
+	}
+	return elts
+}
+
+// assignedValue returns the only value assigned to a local variable within its function,
+// e.g. `&Foo{}` in `var v any = &Foo{}; json.Unmarshal(data, v)` or `json.Marshal` in `marshal := json.Marshal`.
+// For the first of several results, the call is returned, e.g. `f()` in `v, err := f()`.
+// If the variable is assigned more than once, has its address taken, or is not local, nil is returned.
+func assignedValue(info *types.Info, stack []ast.Node, expr ast.Expr) ast.Expr {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := info.Uses[ident].(*types.Var)
+	if !ok {
+		return nil
+	}
+
+	var body *ast.BlockStmt
+	for i := len(stack) - 1; i >= 0 && body == nil; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+	}
+	if body == nil || v.Pos() < body.Pos() || v.Pos() >= body.End() {
+		return nil // e.g. a package-level variable or a parameter.
+	}
+
+	var value ast.Expr
+	ambiguous := false
+	assign := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			if id, ok := l.(*ast.Ident); !ok || info.ObjectOf(id) != v {
+				continue
+			}
+			if (len(lhs) != len(rhs) && (i != 0 || len(rhs) != 1)) || value != nil {
+				ambiguous = true // e.g. `err, v = f()` or a second assignment.
+				return
+			}
+			value = rhs[min(i, len(rhs)-1)] // the call itself for `v, err = f()`.
+		}
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				lhs[i] = name
+			}
+			if len(node.Values) > 0 {
+				assign(lhs, node.Values)
+			}
+		case *ast.AssignStmt:
+			assign(node.Lhs, node.Rhs)
+		case *ast.UnaryExpr:
+			if id, ok := node.X.(*ast.Ident); ok && node.Op == token.AND && info.ObjectOf(id) == v {
+				ambiguous = true // the variable may be assigned through the pointer.
+			}
+		case *ast.RangeStmt:
+			for _, e := range []ast.Expr{node.Key, node.Value} {
+				if id, ok := e.(*ast.Ident); ok && info.ObjectOf(id) == v {
+					ambiguous = true
+				}
+			}
+		}
+		return !ambiguous
+	})
+
+	if ambiguous || value == nil {
+		return nil
+	}
+	if isCall(value) {
+		return value // the result may be known to be concrete, see resultFact.
+	}
+	if typ := info.TypeOf(value); typ == nil || types.IsInterface(typ) {
+		return nil // e.g. `var v any = nil` or another interface.
+	}
+	return value
+}
+
+// singleImplementer returns the only struct type of the package that implements the interface (by value or by pointer),
+// e.g. a sealed interface of a plugin. If there are no or several such types (or the interface is empty), nil is returned.
+func singleImplementer(pkg *types.Package, typ types.Type) types.Type {
+	iface, ok := typ.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return nil
+	}
+
+	var impl types.Type
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		if _, ok := named.Underlying().(*types.Struct); !ok {
+			continue
+		}
+		if !types.Implements(named, iface) && !types.Implements(types.NewPointer(named), iface) {
+			continue
+		}
+		if impl != nil {
+			return nil // several implementers.
+		}
+		impl = named
+	}
+
+	return impl
+}
+
+// elementPos returns the position of the first element of an inline slice, array or map literal,
+// e.g. `{Name: "x"}` in `[]struct{ Name string }{{Name: "x"}}`.
+// For any other expression, its own position is returned.
+func elementPos(expr ast.Expr) token.Pos {
+	lit := expr
+	if unary, ok := lit.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		lit = unary.X
+	}
+	composite, ok := lit.(*ast.CompositeLit)
+	if !ok || len(composite.Elts) == 0 {
+		return expr.Pos()
+	}
+	switch composite.Type.(type) {
+	case *ast.ArrayType, *ast.MapType:
+	default:
+		return expr.Pos()
+	}
+	elt := composite.Elts[0]
+	if kv, ok := elt.(*ast.KeyValueExpr); ok {
+		elt = kv.Value
+	}
+	return elt.Pos()
+}
+
+// enclosingFuncName returns the name of the innermost function declaration in the stack,
+// e.g. "foo" for a function and "T.foo" for a method.
+// If there is no such declaration (e.g. a package-level variable), an empty string is returned.
+func enclosingFuncName(stack []ast.Node) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		decl, ok := stack[i].(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if decl.Recv == nil || len(decl.Recv.List) == 0 {
+			return decl.Name.Name
+		}
+		recv := decl.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		switch expr := recv.(type) {
+		case *ast.IndexExpr: // a generic receiver, e.g. T[E].
+			recv = expr.X
+		case *ast.IndexListExpr: // a generic receiver, e.g. T[K, V].
+			recv = expr.X
+		}
+		if ident, ok := recv.(*ast.Ident); ok {
+			return ident.Name + "." + decl.Name.Name
+		}
+		return decl.Name.Name
+	}
+	return ""
 }
 
 func implementsInterface(typ types.Type, ifaces []string, imports []*types.Package) bool {
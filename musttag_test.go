@@ -1,15 +1,22 @@
 package musttag
 
 import (
+	"go/types"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"go-simpler.org/assert"
 	. "go-simpler.org/assert/EF"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/packages"
 )
 
 func TestAnalyzer(t *testing.T) {
@@ -20,17 +27,596 @@ func TestAnalyzer(t *testing.T) {
 		analyzer := New(
 			Func{Name: "example.com/custom.Marshal", Tag: "custom", ArgPos: 0},
 			Func{Name: "example.com/custom.Unmarshal", Tag: "custom", ArgPos: 1},
+			Func{Name: "example.com/custom.Encode", Tag: "custom", ArgPos: 0},
+			Func{Name: "(*example.com/custom.Codec).Encode", Tag: "custom", ArgPos: 0},
+			Func{Name: "(*example.com/custom.Headers).Marshal", Tag: "header", ArgPos: 0},
+			Func{Name: "(*example.com/custom.Client[T]).SetHeaders", Tag: "header", ArgPos: 0},
 		)
 		analysistest.Run(t, testdata, analyzer, "tests")
 	})
 
+	t.Run("tag per direction", func(t *testing.T) {
+		analyzer := New(
+			Func{Name: "example.com/custom.Marshal", Tag: "enc", ArgPos: 0, Direction: Encode},
+			Func{Name: "example.com/custom.Unmarshal", Tag: "dec", ArgPos: 1, Direction: Decode},
+		)
+		analysistest.Run(t, testdata, analyzer, "tests/perdirection")
+	})
+
+	t.Run("unique names", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("unique-names", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/uniquenames")
+	})
+
+	t.Run("related call sites", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("unique-names", "true")
+		assert.NoErr[F](t, err)
+		res := analysistest.Run(t, testdata, analyzer, "tests/related")[0]
+		assert.Equal[F](t, len(res.Diagnostics), 1)
+		related := res.Diagnostics[0].Related
+		assert.Equal[F](t, len(related), 2)
+		for i, want := range []struct {
+			line    int
+			message string
+		}{
+			{line: 6, message: "triggered by encoding/json.Marshal"},
+			{line: 10, message: "triggered by encoding/json.Unmarshal"},
+		} {
+			posn := res.Pass.Fset.Position(related[i].Pos)
+			assert.Equal[E](t, filepath.Base(posn.Filename), "related.go")
+			assert.Equal[E](t, posn.Line, want.line)
+			assert.Equal[E](t, related[i].Message, want.message)
+		}
+	})
+
+	t.Run("cross tag consistency", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("cross-tag-consistency", "json,yaml")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/crosstags")
+	})
+
+	t.Run("shared tags", func(t *testing.T) {
+		analyzer := New()
+		for name, value := range map[string]string{
+			"shared-tags":           "json,yaml,toml",
+			"cross-tag-consistency": "json,yaml,toml",
+			"per-field":             "true",
+		} {
+			err := analyzer.Flags.Set(name, value)
+			assert.NoErr[F](t, err)
+		}
+		analysistest.Run(t, testdata, analyzer, "tests/sharedtags")
+	})
+
+	t.Run("shared tags mixed", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("shared-tags", "json,xml")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/sharedtags/mixed")
+	})
+
+	t.Run("shared tags only tags", func(t *testing.T) {
+		analyzer := New()
+		for name, value := range map[string]string{
+			"shared-tags": "json,xml",
+			"only-tags":   "xml",
+		} {
+			err := analyzer.Flags.Set(name, value)
+			assert.NoErr[F](t, err)
+		}
+		analysistest.Run(t, testdata, analyzer, "tests/sharedtags/onlyxml")
+	})
+
+	t.Run("skip types", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("skip-types", "tests/skiptypes.Duration,tests/skiptypes.URL")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/skiptypes")
+	})
+
+	for _, defaults := range []string{"on", "off"} {
+		t.Run("default skip types="+defaults, func(t *testing.T) {
+			analyzer := New()
+			for name, value := range map[string]string{
+				"check-embedded-imported": "true",
+				"skip-types":              "example.com/custom.Metadata",
+				"no-default-skip-types":   strconv.FormatBool(defaults == "off"),
+			} {
+				err := analyzer.Flags.Set(name, value)
+				assert.NoErr[F](t, err)
+			}
+			analysistest.Run(t, testdata, analyzer, "tests/skiptypes/defaults/"+defaults)
+		})
+	}
+
+	t.Run("ignore types", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("ignore-types", "tests/ignoretypes.Handle")
+		assert.NoErr[F](t, err)
+		err = analyzer.Flags.Set("check-interfaces", "true") // the default types are ignored anyway.
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/ignoretypes")
+	})
+
+	t.Run("flag empty tag", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("flag-empty-tag", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/emptytags")
+	})
+
+	t.Run("builder methods", func(t *testing.T) {
+		analyzer := New()
+		for _, fn := range []string{
+			"(*github.com/uptrace/bun.InsertQuery).Model:bun:0",
+			"(*github.com/uptrace/bun.SelectQuery).Model:bun:0",
+		} {
+			err := analyzer.Flags.Set("fn", fn)
+			assert.NoErr[F](t, err)
+		}
+		analysistest.Run(t, testdata, analyzer, "tests/bun")
+	})
+
+	t.Run("promoted builder methods", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("fn", "(*github.com/go-pg/pg/v10.baseDB).Model:pg:0")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/gopg")
+	})
+
+	t.Run("require option", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("require-option", "omitempty")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/requireoption")
+	})
+
+	t.Run("ineffective omitempty", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("warn-ineffective-omitempty", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/omitempty")
+	})
+
+	t.Run("list checked", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("list-checked", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/listchecked")
+	})
+
+	t.Run("suggested fixes", func(t *testing.T) {
+		analyzer := New()
+		analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "tests/suggestedfix")
+	})
+
+	t.Run("tag case", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("tag-case", "json:camel")
+		assert.NoErr[F](t, err)
+		analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "tests/tagcase")
+	})
+
+	t.Run("function values", func(t *testing.T) {
+		analyzer := New()
+		analysistest.Run(t, testdata, analyzer, "tests/funcvalues")
+	})
+
+	t.Run("require env prefix", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("require-env-prefix", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/envprefix")
+	})
+
+	t.Run("constrained type parameters", func(t *testing.T) {
+		analyzer := New(
+			Func{Name: "tests/constrained.Marshal", Tag: "json", ArgPos: 0},
+			Func{Name: "tests/constrained.MarshalAccount", Tag: "json", ArgPos: 0},
+		)
+		analysistest.Run(t, testdata, analyzer, "tests/constrained")
+	})
+
+	t.Run("func message", func(t *testing.T) {
+		analyzer := New(Func{Name: "example.com/custom.Marshal", Tag: "log", ArgPos: 0, Message: "add a log tag to the fields of the event"})
+		err := analyzer.Flags.Set("fn", "example.com/custom.Encode:log:0:use the log tag: see the logging guide")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/funcmessage")
+	})
+
+	t.Run("file ignore", func(t *testing.T) {
+		analyzer := New()
+		analysistest.Run(t, testdata, analyzer, "tests/fileignore")
+	})
+
+	t.Run("tag rename", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("tag-rename", "json:j")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/tagrename")
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		analyzer := New(Func{Name: "example.com/custom.Marshal", Tag: "csv", ArgPos: 0})
+		analysistest.Run(t, testdata, analyzer, "tests/csv")
+	})
+
+	t.Run("dot import", func(t *testing.T) {
+		// the structs of dot-imported packages are imported, even though they are spelled as local ones.
+		analyzer := New()
+		res := analysistest.Run(t, testdata, analyzer, "tests/dotimport")[0]
+		categories := make(map[int]string) // by line.
+		for _, diag := range res.Diagnostics {
+			categories[res.Pass.Fset.Position(diag.Pos).Line] = diag.Category
+		}
+		assert.Equal[E](t, categories[19], categoryImported)
+		assert.Equal[E](t, categories[20], categoryImported)
+		assert.Equal[E](t, categories[21], "")
+	})
+
+	t.Run("import gate", func(t *testing.T) {
+		// the packages of custom functions pass the gate too, not only the builtin ones.
+		funcs := map[string]Func{"tests/gated/logf.JSON": {Name: "tests/gated/logf.JSON", Tag: "json"}}
+		analyzer := &analysis.Analyzer{
+			Name: "gate",
+			Doc:  "report the packages that pass the import gate",
+			Run: func(pass *analysis.Pass) (any, error) {
+				if usesFuncs(pass.Pkg, funcs) {
+					pass.Reportf(pass.Files[0].Package, "the functions may be called")
+				}
+				return nil, nil
+			},
+		}
+		analysistest.Run(t, testdata, analyzer, "tests/gated/...")
+	})
+
+	t.Run("method arg pos", func(t *testing.T) {
+		// the receiver is not an argument, so the value is at position 1.
+		analyzer := New(
+			Func{Name: "(*example.com/custom.StreamCodec).Encode", Tag: "custom", ArgPos: 1},
+			Func{Name: "(*example.com/custom.StreamCodec).Decode", Tag: "custom", ArgPos: 1},
+		)
+		analysistest.Run(t, testdata, analyzer, "tests/methodargs")
+	})
+
+	for _, perField := range []string{"on", "off"} {
+		// every missing field is counted, not only the first one of each struct.
+		t.Run("count only per field="+perField, func(t *testing.T) {
+			analyzer := New()
+			err := analyzer.Flags.Set("count-only", "true")
+			assert.NoErr[F](t, err)
+			err = analyzer.Flags.Set("per-field", strconv.FormatBool(perField == "on"))
+			assert.NoErr[F](t, err)
+			analysistest.Run(t, testdata, analyzer, "tests/countonly")
+		})
+	}
+
+	t.Run("require any", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("require-any", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/requireany")
+	})
+
+	t.Run("any key", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("any-key", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/anykey")
+	})
+
+	t.Run("elements", func(t *testing.T) {
+		analyzer := New()
+		analysistest.Run(t, testdata, analyzer, "tests/elements")
+	})
+
+	t.Run("xml options", func(t *testing.T) {
+		analyzer := New()
+		analysistest.Run(t, testdata, analyzer, "tests/xmloptions")
+	})
+
+	t.Run("per field", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("per-field", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/perfield")
+	})
+
+	t.Run("resolve single impl", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("resolve-single-impl", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/singleimpl")
+	})
+
+	t.Run("sorted reports", func(t *testing.T) {
+		analyzer := New()
+		for _, name := range []string{"per-field", "unique-names"} {
+			err := analyzer.Flags.Set(name, "true")
+			assert.NoErr[F](t, err)
+		}
+
+		// the structs are checked in reverse order, yet the reports must be sorted.
+		var first []analysis.Diagnostic
+		for range 3 {
+			diags := analysistest.Run(t, testdata, analyzer, "tests/sorted")[0].Diagnostics
+			for i := 1; i < len(diags); i++ {
+				assert.Equal[F](t, diags[i-1].Pos < diags[i].Pos, true)
+			}
+			if first == nil {
+				first = diags
+				continue
+			}
+			assert.Equal[F](t, len(diags), len(first))
+			for i := range diags {
+				assert.Equal[E](t, diags[i].Message, first[i].Message)
+			}
+		}
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		analyzer := New(
+			Func{Name: "(*text/template.Template).Execute", Tag: "template", ArgPos: 1},
+			Func{Name: "(*text/template.Template).ExecuteTemplate", Tag: "template", ArgPos: 2},
+			Func{Name: "(*html/template.Template).Execute", Tag: "template", ArgPos: 1},
+			Func{Name: "(*html/template.Template).ExecuteTemplate", Tag: "template", ArgPos: 2},
+		)
+		analysistest.Run(t, testdata, analyzer, "tests/templates")
+	})
+
+	t.Run("verbose reports", func(t *testing.T) {
+		analyzer := New(Func{Name: "example.com/custom.Marshal", Tag: "custom", ArgPos: 0})
+		err := analyzer.Flags.Set("verbose-reports", "true")
+		assert.NoErr[F](t, err)
+		diags := analysistest.Run(t, testdata, analyzer, "tests/verbose")[0].Diagnostics
+		assert.Equal[F](t, len(diags), 2)
+		for i, want := range []string{"triggered by encoding/json.Marshal", "triggered by example.com/custom.Marshal"} {
+			assert.Equal[F](t, len(diags[i].Related), 1)
+			assert.Equal[E](t, diags[i].Related[0].Message, want)
+		}
+	})
+
+	t.Run("only tags", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("only-tags", "json,xml")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/onlytags")
+	})
+
+	t.Run("leaves only", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("leaves-only", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/leavesonly")
+	})
+
+	t.Run("accept tags", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("accept-tags", "json,codec")
+		assert.NoErr[F](t, err)
+		err = analyzer.Flags.Set("accept-tags", "json,alias")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/accepttags")
+	})
+
+	t.Run("check interfaces", func(t *testing.T) {
+		analyzer := New()
+		err := analyzer.Flags.Set("check-interfaces", "true")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/checkinterfaces")
+	})
+
+	t.Run("imported category", func(t *testing.T) {
+		analyzer := New()
+		diags := analysistest.Run(t, testdata, analyzer, "tests/imported")[0].Diagnostics
+		assert.Equal[F](t, len(diags), 2)
+		assert.Equal[E](t, diags[0].Category, "")
+		assert.Equal[E](t, diags[1].Category, "musttag/imported")
+	})
+
+	t.Run("too few arguments", func(t *testing.T) {
+		analyzer := New()
+		analysistest.Run(t, testdata, analyzer, "tests/incomplete")
+	})
+
+	t.Run("broken code", func(t *testing.T) {
+		analyzer := New()
+		for _, name := range []string{"unique-names", "check-interfaces", "resolve-single-impl", "check-map-keys"} {
+			err := analyzer.Flags.Set(name, "true")
+			assert.NoErr[F](t, err)
+		}
+		analysistest.Run(t, testdata, analyzer, "tests/broken")
+	})
+
+	t.Run("anonymous nested struct", func(t *testing.T) {
+		analyzer := New()
+		res := analysistest.Run(t, testdata, analyzer, "tests/anonymousnested")[0].Result.(*Result)
+		assert.Equal[F](t, len(res.Findings), 1)
+		assert.Equal[E](t, res.Findings[0].Field, "ID")
+	})
+
+	t.Run("dependent analyzer", func(t *testing.T) {
+		musttag := New()
+		analyzer := &analysis.Analyzer{
+			Name:     "dependent",
+			Doc:      "report the findings of musttag",
+			Requires: []*analysis.Analyzer{musttag},
+			Run: func(pass *analysis.Pass) (any, error) {
+				directions := map[Direction]string{Encode: "encoding", Decode: "decoding"}
+				for _, f := range pass.ResultOf[musttag].(*Result).Findings {
+					pass.Reportf(f.Pos, "%s misses the %s tag on field %s when %s", f.Type, f.Tag, f.Field, directions[f.Direction])
+				}
+				return nil, nil
+			},
+		}
+		analysistest.Run(t, testdata, analyzer, "tests/dependent")
+	})
+
+	t.Run("several analyzers", func(t *testing.T) {
+		a := New(Func{Name: "example.com/custom.Marshal", Tag: "c", ArgPos: 0})
+		b := New()
+		err := b.Flags.Set("fn", "example.com/custom.Marshal:a:0")
+		assert.NoErr[F](t, err)
+
+		for analyzer, want := range map[*analysis.Analyzer]string{a: "c", b: ""} {
+			res := analysistest.Run(nopT{}, testdata, analyzer, "tests/instances")[0].Result.(*Result)
+			if want == "" {
+				assert.Equal[E](t, len(res.Findings), 0)
+				continue
+			}
+			assert.Equal[F](t, len(res.Findings), 1)
+			assert.Equal[E](t, res.Findings[0].Tag, want)
+		}
+	})
+
+	t.Run("sibling fields", func(t *testing.T) {
+		res := analysistest.Run(t, testdata, New(), "tests/siblings")[0].Result.(*Result)
+		var fields []string
+		for _, finding := range res.Findings {
+			fields = append(fields, finding.Field)
+		}
+		assert.Equal[E](t, fields, []string{"A", "B", "A"})
+
+		// in the per-field mode, every field is reported at its declaration, once.
+		analyzer := New()
+		err := analyzer.Flags.Set("per-field", "true")
+		assert.NoErr[F](t, err)
+		res = analysistest.Run(nopT{}, testdata, analyzer, "tests/siblings")[0].Result.(*Result)
+		fields = fields[:0]
+		for _, finding := range res.Findings {
+			fields = append(fields, finding.Field)
+		}
+		assert.Equal[E](t, fields, []string{"A", "B"})
+	})
+
+	t.Run("result pos", func(t *testing.T) {
+		analyzer := New(
+			Func{Name: "tests/resultpos.decodeUser", Tag: "json", ArgPos: ResultPos},
+			Func{Name: "example.com/custom.Decode", Tag: "json", ArgPos: ResultPos},
+		)
+		err := analyzer.Flags.Set("fn", "tests/resultpos.decodeUsers:json:ret")
+		assert.NoErr[F](t, err)
+		analysistest.Run(t, testdata, analyzer, "tests/resultpos")
+	})
+
+	t.Run("result facts", func(t *testing.T) {
+		// the decoders of other packages return interfaces, yet their structs are known via facts.
+		analyzer := New()
+		for _, fn := range []string{"tests/facts/payload.Decode:json:ret", "tests/facts/wrap.Load:json:ret", "tests/facts/payload.DecodeAny:json:ret"} {
+			err := analyzer.Flags.Set("fn", fn)
+			assert.NoErr[F](t, err)
+		}
+		analysistest.Run(t, testdata, analyzer, "tests/facts")
+	})
+
+	t.Run("fact types", func(t *testing.T) {
+		// the facts require analyzing every dependency, so they are only declared for the functions returning a struct.
+		analyzer := New(Func{Name: "example.com/custom.Marshal", Tag: "custom", ArgPos: 0})
+		assert.Equal[E](t, len(analyzer.FactTypes), 0)
+		err := analyzer.Flags.Set("fn", "example.com/custom.Decode:custom:ret")
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, len(analyzer.FactTypes), 1)
+
+		analyzer = New(Func{Name: "example.com/custom.Decode", Tag: "custom", ArgPos: ResultPos})
+		assert.Equal[E](t, len(analyzer.FactTypes), 1)
+	})
+
+	t.Run("bad Func.ArgPos=ResultPos", func(t *testing.T) {
+		analyzer := New(
+			Func{Name: "(*example.com/custom.Client[T]).SetHeaders", Tag: "header", ArgPos: ResultPos},
+		)
+		err := analysistest.Run(nopT{}, testdata, analyzer, "tests")[0].Err
+		assert.Equal[E](t, err.Error(), "musttag: Func.ArgPos cannot be ResultPos: (*example.com/custom.Client[T]).SetHeaders returns nothing")
+	})
+
 	t.Run("bad Func.ArgPos", func(t *testing.T) {
+		// encoding/json calls json.Marshal itself, but the dependencies are only analyzed to export facts.
 		analyzer := New(
 			Func{Name: "encoding/json.Marshal", Tag: "json", ArgPos: 10},
 		)
 		err := analysistest.Run(nopT{}, testdata, analyzer, "tests")[0].Err
 		assert.Equal[E](t, err.Error(), "musttag: Func.ArgPos cannot be 10: encoding/json.Marshal accepts only 1 argument(s)")
 	})
+
+	t.Run("bad Func.ArgPos in a dependency", func(t *testing.T) {
+		// the dependencies are analyzed for facts, yet their calls are validated too.
+		analyzer := New(
+			Func{Name: "encoding/json.Marshal", Tag: "json", ArgPos: 10},
+			Func{Name: "tests/facts/payload.Decode", Tag: "json", ArgPos: ResultPos},
+		)
+		err := analysistest.Run(nopT{}, testdata, analyzer, "tests")[0].Err
+		assert.Equal[E](t, strings.HasPrefix(err.Error(), "failed prerequisites: musttag@encoding/json"), true)
+	})
+
+	for _, strict := range []string{"on", "off"} {
+		t.Run("strict mapstructure="+strict, func(t *testing.T) {
+			analyzer := New()
+			if strict == "on" {
+				err := analyzer.Flags.Set("strict-tags", "mapstructure")
+				assert.NoErr[F](t, err)
+			}
+			analysistest.Run(t, testdata, analyzer, "tests/strict/"+strict)
+		})
+	}
+
+	for _, embedded := range []string{"on", "off"} {
+		t.Run("check embedded imported="+embedded, func(t *testing.T) {
+			analyzer := New()
+			if embedded == "on" {
+				err := analyzer.Flags.Set("check-embedded-imported", "true")
+				assert.NoErr[F](t, err)
+			}
+			analysistest.Run(t, testdata, analyzer, "tests/embeddedimported/"+embedded)
+		})
+	}
+
+	for _, reportAt := range []string{"type", "call"} {
+		t.Run("report at="+reportAt, func(t *testing.T) {
+			analyzer := New()
+			err := analyzer.Flags.Set("per-field", "true")
+			assert.NoErr[F](t, err)
+			err = analyzer.Flags.Set("report-at", reportAt)
+			assert.NoErr[F](t, err)
+			res := analysistest.Run(t, testdata, analyzer, "tests/reportat/"+reportAt)[0]
+			if reportAt == "call" {
+				// the declaration of the field is related information.
+				related := res.Diagnostics[0].Related
+				assert.Equal[F](t, len(related), 1)
+				assert.Equal[E](t, res.Pass.Fset.Position(related[0].Pos).Line, 10)
+			}
+		})
+	}
+
+	for _, mapKeys := range []string{"on", "off"} {
+		t.Run("check map keys="+mapKeys, func(t *testing.T) {
+			analyzer := New()
+			err := analyzer.Flags.Set("check-map-keys", strconv.FormatBool(mapKeys == "on"))
+			assert.NoErr[F](t, err)
+			analysistest.Run(t, testdata, analyzer, "tests/mapkeys/"+mapKeys)
+		})
+	}
+
+	for _, depth := range []string{"1", "2"} {
+		t.Run("max depth="+depth, func(t *testing.T) {
+			analyzer := New()
+			err := analyzer.Flags.Set("max-depth", depth)
+			assert.NoErr[F](t, err)
+			analysistest.Run(t, testdata, analyzer, "tests/maxdepth/depth"+depth)
+		})
+	}
+
+	for _, direction := range []string{"both", "encode", "decode"} {
+		t.Run("direction="+direction, func(t *testing.T) {
+			analyzer := New()
+			err := analyzer.Flags.Set("direction", direction)
+			assert.NoErr[F](t, err)
+			analysistest.Run(t, testdata, analyzer, "tests/direction/"+direction)
+		})
+	}
 }
 
 func TestFlags(t *testing.T) {
@@ -52,12 +638,96 @@ func TestFlags(t *testing.T) {
 		err := analyzer.Flags.Parse([]string{"-fn=test.Test:test:-"})
 		assert.Equal[E](t, err.Error(), `invalid value "test.Test:test:-" for flag -fn: strconv.Atoi: parsing "-": invalid syntax`)
 	})
+
+	t.Run("invalid accept tags", func(t *testing.T) {
+		err := analyzer.Flags.Parse([]string{"-accept-tags=json"})
+		assert.Equal[E](t, err.Error(), `invalid value "json" for flag -accept-tags: invalid syntax`)
+	})
+
+	t.Run("invalid shared tags", func(t *testing.T) {
+		err := analyzer.Flags.Parse([]string{"-shared-tags=json"})
+		assert.Equal[E](t, err.Error(), `invalid value "json" for flag -shared-tags: invalid syntax`)
+	})
+
+	t.Run("invalid skip types", func(t *testing.T) {
+		err := analyzer.Flags.Parse([]string{"-skip-types=Duration"})
+		assert.Equal[E](t, err.Error(), `invalid value "Duration" for flag -skip-types: invalid syntax`)
+	})
+
+	t.Run("invalid ignore types", func(t *testing.T) {
+		err := analyzer.Flags.Parse([]string{"-ignore-types=Client"})
+		assert.Equal[E](t, err.Error(), `invalid value "Client" for flag -ignore-types: invalid syntax`)
+	})
+
+	t.Run("invalid tag case", func(t *testing.T) {
+		err := analyzer.Flags.Parse([]string{"-tag-case=json:title"})
+		assert.Equal[E](t, err.Error(), `invalid value "json:title" for flag -tag-case: invalid syntax`)
+	})
+
+	t.Run("invalid report at", func(t *testing.T) {
+		err := analyzer.Flags.Parse([]string{"-report-at=field"})
+		assert.Equal[E](t, err.Error(), `invalid value "field" for flag -report-at: invalid syntax`)
+	})
+
+	t.Run("invalid tag rename", func(t *testing.T) {
+		err := analyzer.Flags.Parse([]string{"-tag-rename=json"})
+		assert.Equal[E](t, err.Error(), `invalid value "json" for flag -tag-rename: invalid syntax`)
+	})
+
+	t.Run("invalid direction", func(t *testing.T) {
+		err := analyzer.Flags.Parse([]string{"-direction=sideways"})
+		assert.Equal[E](t, err.Error(), `invalid value "sideways" for flag -direction: invalid syntax`)
+	})
 }
 
 type nopT struct{}
 
 func (nopT) Errorf(string, ...any) {}
 
+// BenchmarkRun compares walking every call with skipping a package that does not use any of the functions.
+func BenchmarkRun(b *testing.B) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax}, "net/http") // a large package that imports none of the builtin encoders.
+	assert.NoErr[F](b, err)
+	assert.Equal[F](b, len(pkgs), 1)
+	pkg := pkgs[0]
+
+	pass := &analysis.Pass{
+		Fset:      pkg.Fset,
+		Files:     pkg.Syntax,
+		Pkg:       pkg.Types,
+		TypesInfo: pkg.TypesInfo,
+		ResultOf:  map[*analysis.Analyzer]any{inspect.Analyzer: inspector.New(pkg.Syntax)},
+		Report:    func(analysis.Diagnostic) {},
+
+		ImportObjectFact: func(types.Object, analysis.Fact) bool { return false },
+		ExportObjectFact: func(types.Object, analysis.Fact) {},
+	}
+
+	funcs := make(map[string]Func, len(builtins))
+	for _, fn := range builtins {
+		funcs[funcName(fn.Name)] = fn
+	}
+
+	// the same config as New creates, e.g. with an unlimited -max-depth.
+	var cfg config
+	flags(&cfg)
+
+	b.Run("walk", func(b *testing.B) {
+		for range b.N {
+			_, err := run(pass, "", funcs, &cfg)
+			assert.NoErr[F](b, err)
+		}
+	})
+
+	b.Run("import-gated", func(b *testing.B) {
+		analyzer := New()
+		for range b.N {
+			_, err := analyzer.Run(pass)
+			assert.NoErr[F](b, err)
+		}
+	})
+}
+
 // NOTE: analysistest does not yet support modules;
 // see https://github.com/golang/go/issues/37054 for details.
 func setupModules(t *testing.T, testdata string) {
@@ -1,4 +1,42 @@
 package custom
 
+import "io"
+
 func Marshal(any) ([]byte, error) { return nil, nil }
 func Unmarshal([]byte, any) error { return nil }
+
+func Encode[T any](T) ([]byte, error) { return nil, nil }
+
+type Codec[T any] struct{}
+
+func (*Codec[T]) Encode(T) ([]byte, error) { return nil, nil }
+
+type Headers struct{}
+
+func (*Headers) Marshal(any) (map[string]string, error) { return nil, nil }
+
+type Client[T any] struct{}
+
+func (*Client[T]) SetHeaders(any) {}
+
+func Decode[T any]([]byte) (T, error) { return *new(T), nil }
+
+type StreamCodec struct{}
+
+func (*StreamCodec) Encode(w io.Writer, v any) error { return nil }
+func (*StreamCodec) Decode(r io.Reader, v any) error { return nil }
+
+type Metadata struct {
+	Author  string
+	Version string
+	Source  Source
+}
+
+type Record struct {
+	ID     string `json:"id"`
+	Source Source `json:"source"`
+}
+
+type Source struct {
+	URL string
+}
@@ -0,0 +1,11 @@
+// Package binding is a stub of github.com/gin-gonic/gin/binding.
+package binding
+
+type Binding interface {
+	Name() string
+	Bind(any, any) error
+}
+
+type BindUnmarshaler interface {
+	UnmarshalParam(param string) error
+}
@@ -0,0 +1,23 @@
+// Package gin is a stub of github.com/gin-gonic/gin;
+// the real module cannot be vendored in GOPATH mode because it depends on golang.org/x/net.
+package gin
+
+import "github.com/gin-gonic/gin/binding"
+
+type Context struct{}
+
+func (*Context) BindJSON(any) error                        { return nil }
+func (*Context) BindXML(any) error                         { return nil }
+func (*Context) BindYAML(any) error                        { return nil }
+func (*Context) BindTOML(any) error                        { return nil }
+func (*Context) BindQuery(any) error                       { return nil }
+func (*Context) BindHeader(any) error                      { return nil }
+func (*Context) BindUri(any) error                         { return nil }
+func (*Context) ShouldBindJSON(any) error                  { return nil }
+func (*Context) ShouldBindXML(any) error                   { return nil }
+func (*Context) ShouldBindYAML(any) error                  { return nil }
+func (*Context) ShouldBindTOML(any) error                  { return nil }
+func (*Context) ShouldBindQuery(any) error                 { return nil }
+func (*Context) ShouldBindHeader(any) error                { return nil }
+func (*Context) ShouldBindUri(any) error                   { return nil }
+func (*Context) ShouldBindWith(any, binding.Binding) error { return nil }
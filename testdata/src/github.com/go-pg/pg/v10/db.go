@@ -0,0 +1,17 @@
+// Package pg is a stub of github.com/go-pg/pg/v10;
+// only the query builder is needed to test registering its methods as custom functions.
+package pg
+
+type baseDB struct{}
+
+type DB struct {
+	*baseDB
+}
+
+func (*baseDB) Model(...any) *Query { return new(Query) }
+
+type Query struct{}
+
+func (q *Query) Where(string, ...any) *Query { return q }
+func (*Query) Select(...any) error           { return nil }
+func (*Query) Insert(...any) (any, error)    { return nil, nil }
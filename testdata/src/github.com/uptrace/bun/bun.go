@@ -0,0 +1,24 @@
+// Package bun is a stub of github.com/uptrace/bun;
+// only the query builders are needed to test registering their methods as custom functions.
+package bun
+
+import (
+	"context"
+	"database/sql"
+)
+
+type DB struct{}
+
+func (*DB) NewInsert() *InsertQuery { return new(InsertQuery) }
+func (*DB) NewSelect() *SelectQuery { return new(SelectQuery) }
+
+type InsertQuery struct{}
+
+func (q *InsertQuery) Model(any) *InsertQuery                         { return q }
+func (*InsertQuery) Exec(context.Context, ...any) (sql.Result, error) { return nil, nil }
+
+type SelectQuery struct{}
+
+func (q *SelectQuery) Model(any) *SelectQuery            { return q }
+func (q *SelectQuery) Where(string, ...any) *SelectQuery { return q }
+func (*SelectQuery) Scan(context.Context, ...any) error  { return nil }
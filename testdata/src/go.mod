@@ -5,9 +5,34 @@ go 1.20
 require (
 	example.com/custom v0.1.0
 	github.com/BurntSushi/toml v1.3.2
+	github.com/anacrolix/torrent v1.53.3
+	github.com/caarlos0/env/v11 v11.0.0
+	github.com/gin-gonic/gin v1.9.1
+	github.com/go-pg/pg/v10 v10.11.1
+	github.com/go-playground/form/v4 v4.2.1
+	github.com/google/go-querystring v1.1.0
+	github.com/gorilla/schema v1.4.1
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/mitchellh/mapstructure v1.5.0
+	github.com/redis/go-redis/v9 v9.0.5
+	github.com/uptrace/bun v1.1.17
 	gopkg.in/yaml.v3 v3.0.1
+	howett.net/plist v1.0.1
 )
 
-replace example.com/custom => ./example.com/custom
+require (
+	github.com/anacrolix/missinggo v1.3.0 // indirect
+	github.com/anacrolix/missinggo/v2 v2.7.2-0.20230527121029-a582b4f397b9 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/huandu/xstrings v1.3.2 // indirect
+	github.com/mattn/go-sqlite3 v1.14.16 // indirect
+)
+
+replace (
+	example.com/custom => ./example.com/custom
+	github.com/gin-gonic/gin => ./github.com/gin-gonic/gin
+	github.com/go-pg/pg/v10 => ./github.com/go-pg/pg/v10
+	github.com/uptrace/bun => ./github.com/uptrace/bun
+	howett.net/plist => ./howett.net/plist
+)
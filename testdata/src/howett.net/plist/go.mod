@@ -0,0 +1,3 @@
+module howett.net/plist
+
+go 1.20
@@ -0,0 +1,36 @@
+// Package plist is a stub of howett.net/plist;
+// the module cannot be downloaded from the proxy, so only the API needed by the tests is declared.
+package plist
+
+import "io"
+
+const (
+	InvalidFormat int = iota
+	XMLFormat
+	BinaryFormat
+	OpenStepFormat
+	GNUStepFormat
+)
+
+type Marshaler interface {
+	MarshalPlist() (any, error)
+}
+
+type Unmarshaler interface {
+	UnmarshalPlist(unmarshal func(any) error) error
+}
+
+func Marshal(v any, format int) ([]byte, error)                      { return nil, nil }
+func MarshalIndent(v any, format int, indent string) ([]byte, error) { return nil, nil }
+func Unmarshal(data []byte, v any) (format int, err error)           { return 0, nil }
+
+type Encoder struct{}
+
+func NewEncoder(w io.Writer) *Encoder                      { return new(Encoder) }
+func NewEncoderForFormat(w io.Writer, format int) *Encoder { return new(Encoder) }
+func (*Encoder) Encode(v any) error                        { return nil }
+
+type Decoder struct{}
+
+func NewDecoder(r io.ReadSeeker) *Decoder { return new(Decoder) }
+func (*Decoder) Decode(v any) error       { return nil }
@@ -0,0 +1,28 @@
+package accepttags
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+type Fallback struct {
+	Primary string `json:"primary"`
+	Codec   string `codec:"codec"`
+	Alias   string `alias:"alias"`
+	Ignored string `codec:"-"`
+}
+
+type Unrelated struct {
+	Primary string `json:"primary"`
+	Other   string `other:"other"`
+}
+
+type XMLOnly struct {
+	Codec string `codec:"codec"`
+}
+
+func test() {
+	json.Marshal(Fallback{})
+	json.Marshal(Unrelated{}) // want "the given struct should be annotated with the `json` tag"
+	xml.Marshal(XMLOnly{})    // want "the given struct should be annotated with the `xml` tag"
+}
@@ -0,0 +1,15 @@
+package anonymousnested
+
+import "encoding/json"
+
+type T struct {
+	Name string `json:"name"`
+	Meta struct {
+		Version int `json:"version"`
+		ID      int
+	} `json:"meta"`
+}
+
+func test() {
+	json.Marshal(T{}) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,35 @@
+package anykey
+
+import "encoding/json"
+
+type Untagged struct {
+	ID   int
+	Name string
+}
+
+type OtherKeys struct {
+	ID   int    `yaml:"id"`
+	Name string `db:"name" validate:"required"`
+}
+
+type Mixed struct {
+	ID   int `json:"id"`
+	Name string
+}
+
+type Malformed struct {
+	ID   int    `yaml:id`
+	Name string `json:"name"`
+}
+
+type Nested struct {
+	Inner Untagged `yaml:"inner"`
+}
+
+func test() {
+	json.Marshal(Untagged{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(OtherKeys{})
+	json.Marshal(Mixed{})     // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Malformed{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Nested{})    // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,41 @@
+package broken
+
+import "encoding/json"
+
+type Undefined struct {
+	Field  UndefinedType  `json:"field"`
+	Nested missing.Type   `json:"nested"`
+	Ptr    *AlsoUndefined `json:"ptr"`
+}
+
+type Recursive Recursive
+
+type Broken struct {
+	Name string          `json:"name"`
+	Bad  []UndefinedType `json:"bad"`
+}
+
+type Iface interface{ Method(UndefinedType) }
+
+type Impl struct{ NoTag string }
+
+func (Impl) Method(UndefinedType) {}
+
+func test(i Iface) {
+	json.Marshal(Undefined{})
+	json.Marshal(Recursive{})
+	json.Marshal(Broken{})
+	json.Marshal(undefinedVar)
+	json.Marshal(undefinedFunc())
+	json.Marshal(missing.Value)
+	json.Unmarshal(nil, &missing.Type{})
+	json.Marshal(i)                        // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(map[UndefinedType]Impl{}) // want "the given struct should be annotated with the `json` tag"
+
+	var v any = undefinedFunc()
+	json.Unmarshal(nil, v)
+
+	missing.Marshal(Impl{})
+	json.NewEncoder(nil).Encode(Impl{}, 1) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Impl{})                   // want "the given struct should be annotated with the `json` tag"
+}
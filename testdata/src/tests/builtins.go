@@ -1,14 +1,23 @@
 package tests
 
 import (
+	"encoding/asn1"
 	"encoding/json"
 	"encoding/xml"
 
 	"example.com/custom"
 	"github.com/BurntSushi/toml"
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/caarlos0/env/v11"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/form/v4"
+	"github.com/google/go-querystring/query"
+	"github.com/gorilla/schema"
 	"github.com/jmoiron/sqlx"
 	"github.com/mitchellh/mapstructure"
+	"github.com/redis/go-redis/v9"
 	"gopkg.in/yaml.v3"
+	"howett.net/plist"
 )
 
 type Struct struct{ NoTag string }
@@ -22,12 +31,20 @@ func (*Marshaler) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error { r
 func (Marshaler) MarshalYAML() (any, error)                                  { return nil, nil }
 func (*Marshaler) UnmarshalYAML(*yaml.Node) error                            { return nil }
 func (*Marshaler) UnmarshalTOML(any) error                                   { return nil }
+func (Marshaler) MarshalBencode() ([]byte, error)                            { return nil, nil }
+func (*Marshaler) UnmarshalBencode([]byte) error                             { return nil }
+func (Marshaler) MarshalPlist() (any, error)                                 { return nil, nil }
+func (*Marshaler) UnmarshalPlist(func(any) error) error                      { return nil }
 
 type TextMarshaler struct{ NoTag string }
 
 func (TextMarshaler) MarshalText() ([]byte, error) { return nil, nil }
 func (*TextMarshaler) UnmarshalText([]byte) error  { return nil }
 
+type ParamUnmarshaler struct{ NoTag string }
+
+func (*ParamUnmarshaler) UnmarshalParam(string) error { return nil }
+
 type Scanner struct{ NotTag string }
 
 func (*Scanner) Scan(any) error { return nil }
@@ -91,6 +108,34 @@ func testYAML() {
 	yaml.NewEncoder(nil).Encode(st)  // want "the given struct should be annotated with the `yaml` tag"
 	yaml.NewDecoder(nil).Decode(&st) // want "the given struct should be annotated with the `yaml` tag"
 
+	type Inline struct {
+		Name string
+	}
+	type Inlined struct {
+		Inline `yaml:",inline"`
+	}
+	yaml.Marshal(Inlined{}) // want "the given struct should be annotated with the `yaml` tag"
+
+	type Item struct {
+		Name string `yaml:"name"`
+	}
+	type Flow struct {
+		Items []Item `yaml:",flow"`
+	}
+	yaml.Marshal(Flow{})
+
+	// yaml.Node holds the raw document, like json.RawMessage.
+	type Raw struct {
+		Doc  yaml.Node   `yaml:"doc"`
+		Ptr  *yaml.Node  `yaml:"ptr"`
+		List []yaml.Node `yaml:"list"`
+	}
+	yaml.Unmarshal(nil, &Raw{})
+	type Untagged struct {
+		Doc yaml.Node
+	}
+	yaml.Unmarshal(nil, &Untagged{}) // want "the given struct should be annotated with the `yaml` tag"
+
 	var m Marshaler
 	yaml.Marshal(m)
 	yaml.Unmarshal(nil, &m)
@@ -107,6 +152,33 @@ func testTOML() {
 	toml.NewEncoder(nil).Encode(st)  // want "the given struct should be annotated with the `toml` tag"
 	toml.NewDecoder(nil).Decode(&st) // want "the given struct should be annotated with the `toml` tag"
 
+	// the elements of arrays of tables and inline tables are checked as well.
+	type Server struct {
+		Host string `toml:"host"`
+		Port int
+	}
+	type Endpoint struct {
+		URL string
+	}
+	type Servers struct {
+		Servers []Server `toml:"servers"`
+	}
+	type Endpoints struct {
+		Endpoints map[string]Endpoint `toml:"endpoints"`
+	}
+	type Node struct {
+		Name string `toml:"name"`
+	}
+	type Tagged struct {
+		Nodes     [2]*Node `toml:"nodes"`
+		Endpoints map[string][]struct {
+			URL string `toml:"url"`
+		} `toml:"endpoints"`
+	}
+	toml.Unmarshal(nil, &Servers{})   // want "the given struct should be annotated with the `toml` tag"
+	toml.Unmarshal(nil, &Endpoints{}) // want "the given struct should be annotated with the `toml` tag"
+	toml.Unmarshal(nil, &Tagged{})
+
 	var m Marshaler
 	toml.Unmarshal(nil, &m)
 	toml.Decode("", &m)
@@ -123,12 +195,311 @@ func testTOML() {
 	toml.NewDecoder(nil).Decode(&tm)
 }
 
+func testBencode() {
+	var st Struct
+	bencode.Marshal(st)                 // want "the given struct should be annotated with the `bencode` tag"
+	bencode.Unmarshal(nil, &st)         // want "the given struct should be annotated with the `bencode` tag"
+	bencode.NewEncoder(nil).Encode(st)  // want "the given struct should be annotated with the `bencode` tag"
+	bencode.NewDecoder(nil).Decode(&st) // want "the given struct should be annotated with the `bencode` tag"
+
+	type Info struct {
+		Name   string `bencode:"name"`
+		Length int64
+	}
+	type MetaInfo struct {
+		Announce string `bencode:"announce"`
+		Info     Info   `bencode:"info"`
+	}
+	var mi MetaInfo
+	bencode.Marshal(mi)         // want "the given struct should be annotated with the `bencode` tag"
+	bencode.Unmarshal(nil, &mi) // want "the given struct should be annotated with the `bencode` tag"
+
+	var m Marshaler
+	bencode.Marshal(m)
+	bencode.Unmarshal(nil, &m)
+	bencode.NewEncoder(nil).Encode(m)
+	bencode.NewDecoder(nil).Decode(&m)
+}
+
+func testSchema() {
+	var st Struct
+	schema.NewDecoder().Decode(&st, nil) // want "the given struct should be annotated with the `schema` tag"
+	schema.NewEncoder().Encode(st, nil)  // want "the given struct should be annotated with the `schema` tag"
+
+	type Address struct {
+		Street string `schema:"street"`
+		City   string
+	}
+	type Form struct {
+		Name    string  `schema:"name"`
+		Address Address `schema:"address"`
+	}
+	var f Form
+	schema.NewDecoder().Decode(&f, nil) // want "the given struct should be annotated with the `schema` tag"
+
+	type Filter struct {
+		Query string   `schema:"q,omitempty"`
+		Tags  []string `schema:",omitempty"`
+		Page  int      `schema:"page,required"`
+		Skip  string   `schema:"-"`
+	}
+	schema.NewEncoder().Encode(Filter{}, nil)
+	schema.NewDecoder().Decode(&Filter{}, nil)
+
+	var tm TextMarshaler
+	schema.NewDecoder().Decode(&tm, nil)
+}
+
+func testGin() {
+	var st Struct
+	c := new(gin.Context)
+	c.BindJSON(&st)         // want "the given struct should be annotated with the `json` tag"
+	c.BindXML(&st)          // want "the given struct should be annotated with the `xml` tag"
+	c.BindYAML(&st)         // want "the given struct should be annotated with the `yaml` tag"
+	c.BindTOML(&st)         // want "the given struct should be annotated with the `toml` tag"
+	c.BindQuery(&st)        // want "the given struct should be annotated with the `form` tag"
+	c.BindHeader(&st)       // want "the given struct should be annotated with the `header` tag"
+	c.BindUri(&st)          // want "the given struct should be annotated with the `uri` tag"
+	c.ShouldBindJSON(&st)   // want "the given struct should be annotated with the `json` tag"
+	c.ShouldBindXML(&st)    // want "the given struct should be annotated with the `xml` tag"
+	c.ShouldBindYAML(&st)   // want "the given struct should be annotated with the `yaml` tag"
+	c.ShouldBindTOML(&st)   // want "the given struct should be annotated with the `toml` tag"
+	c.ShouldBindQuery(&st)  // want "the given struct should be annotated with the `form` tag"
+	c.ShouldBindHeader(&st) // want "the given struct should be annotated with the `header` tag"
+	c.ShouldBindUri(&st)    // want "the given struct should be annotated with the `uri` tag"
+
+	type Params struct {
+		ID   string `uri:"id"`
+		Page int    `form:"page"`
+	}
+	var p Params
+	c.ShouldBindUri(&p)   // want "the given struct should be annotated with the `uri` tag"
+	c.ShouldBindQuery(&p) // want "the given struct should be annotated with the `form` tag"
+
+	var m Marshaler
+	c.BindJSON(&m)
+	c.BindXML(&m)
+	c.BindYAML(&m)
+	c.ShouldBindJSON(&m)
+	c.ShouldBindXML(&m)
+	c.ShouldBindYAML(&m)
+
+	var tm TextMarshaler
+	c.BindJSON(&tm)
+	c.BindXML(&tm)
+	c.BindTOML(&tm)
+	c.ShouldBindJSON(&tm)
+	c.ShouldBindXML(&tm)
+	c.ShouldBindTOML(&tm)
+
+	var pu ParamUnmarshaler
+	c.BindQuery(&pu)
+	c.BindHeader(&pu)
+	c.BindUri(&pu)
+	c.ShouldBindQuery(&pu)
+	c.ShouldBindHeader(&pu)
+	c.ShouldBindUri(&pu)
+}
+
+func testForm() {
+	var st Struct
+	form.NewDecoder().Decode(&st, nil) // want "the given struct should be annotated with the `form` tag"
+	form.NewEncoder().Encode(st)       // want "the given struct should be annotated with the `form` tag"
+
+	type Address struct {
+		Street string `form:"street"`
+		City   string
+	}
+	type User struct {
+		Name      string    `form:"name"`
+		Addresses []Address `form:"addresses"`
+	}
+	var u User
+	form.NewDecoder().Decode(&u, nil) // want "the given struct should be annotated with the `form` tag"
+	form.NewEncoder().Encode(u)       // want "the given struct should be annotated with the `form` tag"
+
+	type TaggedAddress struct {
+		Street string `form:"street"`
+		City   string `form:"city"`
+	}
+	type TaggedUser struct {
+		Name      string                   `form:"name"`
+		Addresses []TaggedAddress          `form:"addresses"`
+		ByKind    map[string]TaggedAddress `form:"by_kind"`
+	}
+	var tu TaggedUser
+	form.NewDecoder().Decode(&tu, nil)
+	form.NewEncoder().Encode(tu)
+}
+
+func testQuery() {
+	var st Struct
+	query.Values(st)  // want "the given struct should be annotated with the `url` tag"
+	query.Values(&st) // want "the given struct should be annotated with the `url` tag"
+
+	type Page struct {
+		Number int
+		Size   int `url:"size"`
+	}
+	type Options struct {
+		Query string `url:"q"`
+		Page  Page   `url:"page"`
+	}
+	query.Values(Options{}) // want "the given struct should be annotated with the `url` tag"
+
+	type Filter struct {
+		Name string `url:"name"`
+	}
+	type TaggedOptions struct {
+		Query   string   `url:"q"`
+		Filters []Filter `url:"filters"`
+	}
+	query.Values(TaggedOptions{})
+}
+
+func testASN1() {
+	var st Struct
+	asn1.Marshal(st)                       // want "the given struct should be annotated with the `asn1` tag"
+	asn1.MarshalWithParams(st, "")         // want "the given struct should be annotated with the `asn1` tag"
+	asn1.Unmarshal(nil, &st)               // want "the given struct should be annotated with the `asn1` tag"
+	asn1.UnmarshalWithParams(nil, &st, "") // want "the given struct should be annotated with the `asn1` tag"
+
+	type Validity struct {
+		NotBefore string `asn1:"utc"`
+		NotAfter  string
+	}
+	type Certificate struct {
+		Version  int      `asn1:"optional,explicit,default:0,tag:0"`
+		Validity Validity `asn1:"sequence"`
+	}
+	var cert Certificate
+	asn1.Unmarshal(nil, &cert) // want "the given struct should be annotated with the `asn1` tag"
+}
+
+func testRedis() {
+	var st Struct
+	rdb := redis.NewClient(nil)
+	rdb.HSet(nil, "k", st)          // want "the given struct should be annotated with the `redis` tag"
+	rdb.HSet(nil, "k", &st)         // want "the given struct should be annotated with the `redis` tag"
+	rdb.HMSet(nil, "k", st)         // want "the given struct should be annotated with the `redis` tag"
+	rdb.HSet(nil, "k", "field", st) // want "the given struct should be annotated with the `redis` tag"
+	rdb.HSet(nil, "k", "field", "value")
+	rdb.HSet(nil, "k", []any{"field", "value"}...)
+
+	type Payload struct {
+		ID    string `redis:"id"`
+		Score int    `redis:"score"`
+	}
+	rdb.HSet(nil, "k", Payload{})
+}
+
+func testEnv() {
+	var st Struct
+	env.Parse(&st)                                // want "the given struct should be annotated with the `env` tag"
+	env.ParseWithOptions(&st, env.Options{})      // want "the given struct should be annotated with the `env` tag"
+	env.ParseAs[Struct]()                         // want "the given struct should be annotated with the `env` tag"
+	env.ParseAsWithOptions[Struct](env.Options{}) // want "the given struct should be annotated with the `env` tag"
+
+	type Database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+	type Config struct {
+		Name     string   `env:"NAME"`
+		Database Database `envPrefix:"DB_"`
+		Replica  *Database
+	}
+	env.Parse(&Config{})
+	env.Must(env.ParseAs[Config]())
+
+	type Cache struct {
+		TTL int
+	}
+	type Service struct {
+		Cache Cache `envPrefix:"CACHE_"`
+	}
+	env.Parse(&Service{}) // want "the given struct should be annotated with the `env` tag"
+}
+
+func testPlist() {
+	var st Struct
+	plist.Marshal(st, plist.XMLFormat)                            // want "the given struct should be annotated with the `plist` tag"
+	plist.MarshalIndent(st, plist.XMLFormat, "")                  // want "the given struct should be annotated with the `plist` tag"
+	plist.Unmarshal(nil, &st)                                     // want "the given struct should be annotated with the `plist` tag"
+	plist.NewEncoder(nil).Encode(st)                              // want "the given struct should be annotated with the `plist` tag"
+	plist.NewEncoderForFormat(nil, plist.BinaryFormat).Encode(st) // want "the given struct should be annotated with the `plist` tag"
+	plist.NewDecoder(nil).Decode(&st)                             // want "the given struct should be annotated with the `plist` tag"
+
+	type Settings struct {
+		Name   string `plist:"name"`
+		Window struct {
+			Width int
+		} `plist:"window"`
+	}
+	plist.Marshal(Settings{}, plist.XMLFormat) // want "the given struct should be annotated with the `plist` tag"
+
+	var m Marshaler
+	plist.Marshal(m, plist.XMLFormat)
+	plist.Unmarshal(nil, &m)
+	plist.NewEncoder(nil).Encode(m)
+	plist.NewDecoder(nil).Decode(&m)
+
+	var tm TextMarshaler
+	plist.Marshal(tm, plist.XMLFormat)
+	plist.Unmarshal(nil, &tm)
+}
+
 func testMapstructure() {
 	var st Struct
 	mapstructure.Decode(nil, &st)                  // want "the given struct should be annotated with the `mapstructure` tag"
 	mapstructure.DecodeMetadata(nil, &st, nil)     // want "the given struct should be annotated with the `mapstructure` tag"
 	mapstructure.WeakDecode(nil, &st)              // want "the given struct should be annotated with the `mapstructure` tag"
 	mapstructure.WeakDecodeMetadata(nil, &st, nil) // want "the given struct should be annotated with the `mapstructure` tag"
+
+	type Extra struct {
+		Value string
+	}
+	type Base struct {
+		ID   string `mapstructure:"id"`
+		Name string
+	}
+	type Config struct {
+		Base  `mapstructure:",squash"`
+		Port  int              `mapstructure:"port"`
+		Other map[string]Extra `mapstructure:",remain"`
+	}
+	var cfg Config
+	mapstructure.Decode(nil, &cfg) // want "the given struct should be annotated with the `mapstructure` tag"
+
+	type TaggedBase struct {
+		ID   string `mapstructure:"id"`
+		Name string `mapstructure:"name"`
+	}
+	type TaggedConfig struct {
+		TaggedBase `mapstructure:",squash"`
+		Port       int              `mapstructure:"port"`
+		Other      map[string]Extra `mapstructure:",remain"`
+	}
+	var tcfg TaggedConfig
+	mapstructure.Decode(nil, &tcfg)
+}
+
+func testSeveralTags() {
+	var st Struct
+	json.Marshal(st)              // want "the given struct should be annotated with the `json` tag"
+	mapstructure.Decode(nil, &st) // want "the given struct should be annotated with the `mapstructure` tag"
+	json.Unmarshal(nil, &st)      // want "the given struct should be annotated with the `json` tag"
+	mapstructure.Decode(nil, &st) // want "the given struct should be annotated with the `mapstructure` tag"
+
+	type Nested struct {
+		Value string `json:"value"`
+	}
+	type Config struct {
+		Nested Nested `json:"nested" mapstructure:"nested"`
+	}
+	var cfg Config
+	json.Marshal(cfg)
+	mapstructure.Decode(nil, &cfg) // want "the given struct should be annotated with the `mapstructure` tag"
 }
 
 func testSQLX() {
@@ -192,3 +563,22 @@ func testCustom() {
 	custom.Marshal(st)         // want "the given struct should be annotated with the `custom` tag"
 	custom.Unmarshal(nil, &st) // want "the given struct should be annotated with the `custom` tag"
 }
+
+func testCustomGeneric() {
+	var st Struct
+	custom.Encode(st)                    // want "the given struct should be annotated with the `custom` tag"
+	custom.Encode[Struct](st)            // want "the given struct should be annotated with the `custom` tag"
+	new(custom.Codec[Struct]).Encode(st) // want "the given struct should be annotated with the `custom` tag"
+}
+
+func testCustomMethod() {
+	var st Struct
+	new(custom.Headers).Marshal(st)            // want "the given struct should be annotated with the `header` tag"
+	new(custom.Client[Struct]).SetHeaders(&st) // want "the given struct should be annotated with the `header` tag"
+
+	type Request struct {
+		Auth  string `header:"Authorization"`
+		Trace string `header:"X-Trace-Id"`
+	}
+	new(custom.Headers).Marshal(Request{})
+}
@@ -0,0 +1,61 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type User struct {
+	ID   int64 `bun:"id,pk,autoincrement"`
+	Name string
+}
+
+type Book struct {
+	ID    int64  `bun:"id,pk"`
+	Title string `bun:"title"`
+}
+
+// NullTime is stored in a single column, so its fields do not require tags.
+type NullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *NullTime) Scan(any) error              { return nil }
+func (t NullTime) Value() (driver.Value, error) { return nil, nil }
+
+type Article struct {
+	ID        int64          `bun:"id,pk"`
+	Title     sql.NullString `bun:"title"`
+	Published NullTime       `bun:"published"`
+	Archived  *NullTime      `bun:"archived"`
+}
+
+// Draft still requires the tags on the fields of sql.Scanner types.
+type Draft struct {
+	ID      int64 `bun:"id,pk"`
+	Created NullTime
+}
+
+func test(ctx context.Context, db *bun.DB) {
+	var user User
+	db.NewInsert().Model(&user).Exec(ctx)                    // want "the given struct should be annotated with the `bun` tag"
+	db.NewSelect().Model(&user).Where("id = ?", 1).Scan(ctx) // want "the given struct should be annotated with the `bun` tag"
+
+	var books []Book
+	db.NewInsert().Model(&books).Exec(ctx)
+	db.NewSelect().Model(&books).Scan(ctx)
+}
+
+func testScanners(ctx context.Context, db *bun.DB) {
+	var article Article
+	db.NewInsert().Model(&article).Exec(ctx)
+	db.NewSelect().Model(&article).Scan(ctx)
+
+	var draft Draft
+	db.NewInsert().Model(&draft).Exec(ctx) // want "the given struct should be annotated with the `bun` tag"
+}
@@ -0,0 +1,24 @@
+package checkinterfaces
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type WithError struct {
+	Err error
+}
+
+type WithAny struct {
+	Value any
+}
+
+type WithStringer struct {
+	fmt.Stringer
+}
+
+func test() {
+	json.Marshal(WithError{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(WithAny{})   // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(WithStringer{})
+}
@@ -0,0 +1,31 @@
+package constrained
+
+import "encoding/json"
+
+type User struct {
+	Name string
+}
+
+type Admin struct {
+	Name string `json:"name"`
+}
+
+type Encodable interface {
+	~struct{ Name string }
+}
+
+type Account interface {
+	User | Admin
+}
+
+// Marshal is registered as a custom function, its argument is checked at every call site.
+func Marshal[T Encodable](v T) ([]byte, error) { return json.Marshal(v) }
+
+func MarshalAccount[T Account](v *T) ([]byte, error) { return json.Marshal(v) }
+
+func test() {
+	Marshal(User{})         // want "the given struct should be annotated with the `json` tag"
+	Marshal[User](User{})   // want "the given struct should be annotated with the `json` tag"
+	MarshalAccount(&User{}) // want "the given struct should be annotated with the `json` tag"
+	MarshalAccount(&Admin{})
+}
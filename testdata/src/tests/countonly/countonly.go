@@ -0,0 +1,35 @@
+package countonly // want "6 field\\(s\\) across 4 struct\\(s\\) missing tags"
+
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	"tests/countonly/models"
+)
+
+type User struct {
+	ID   int
+	Name string
+	Team Team `json:"team"`
+}
+
+type Team struct {
+	Title string
+}
+
+type Tagged struct {
+	Name string `json:"name" xml:"name"`
+}
+
+type Order struct {
+	ID int
+}
+
+func test() {
+	json.Marshal(User{})
+	json.Unmarshal(nil, &User{})
+	json.Marshal(Tagged{})
+	json.Marshal(Order{})
+	xml.Marshal(Tagged{})
+	json.Marshal(models.Profile{})
+}
@@ -0,0 +1,6 @@
+package models
+
+type Profile struct {
+	Bio     string
+	Website string
+}
@@ -0,0 +1,23 @@
+package crosstags
+
+import "encoding/json"
+
+type Object struct {
+	Name      string `json:"name" yaml:"name"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Labels    string `json:"labels" yaml:"tags"` // want "the `yaml` tag name \"tags\" of field Labels does not match the `json` tag name \"labels\""
+	Spec      Spec   `json:"spec" yaml:"spec"`
+	Status    string `json:"status"`
+	Internal  string `json:"-" yaml:"internal"`
+	Default   string `json:",omitempty" yaml:"default"`
+}
+
+type Spec struct {
+	Replicas int `json:"replicas" yaml:"replicaCount"` // want "the `yaml` tag name \"replicaCount\" of field Replicas does not match the `json` tag name \"replicas\""
+}
+
+func test() {
+	json.Marshal(Object{})
+	json.Marshal(&Object{})
+	json.Unmarshal(nil, &Object{})
+}
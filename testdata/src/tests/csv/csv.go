@@ -0,0 +1,27 @@
+package csv
+
+import "example.com/custom"
+
+type Address struct {
+	City string `csv:"city"`
+}
+
+type Row struct {
+	Name    string `csv:"name"`
+	Address Address
+	Phone   *Phone
+}
+
+type Phone struct {
+	Number string
+}
+
+type Untagged struct {
+	Name string
+}
+
+func test() {
+	custom.Marshal(Row{}) // want "the given struct should be annotated with the `csv` tag"
+	custom.Marshal(Address{})
+	custom.Marshal(Untagged{}) // want "the given struct should be annotated with the `csv` tag"
+}
@@ -0,0 +1,16 @@
+package dependent
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+type User struct {
+	Name string
+}
+
+func test() {
+	json.Marshal(User{})         // want "User misses the json tag on field Name when encoding"
+	json.Unmarshal(nil, &User{}) // want `\*User misses the json tag on field Name when decoding`
+	xml.Marshal([]User{})        // want `\[\]User misses the xml tag on field Name when encoding`
+}
@@ -0,0 +1,14 @@
+package both
+
+import "encoding/json"
+
+type Struct struct{ NoTag string }
+
+func test() {
+	var st Struct
+	json.Marshal(st)                 // want "the given struct should be annotated with the `json` tag"
+	json.MarshalIndent(st, "", "")   // want "the given struct should be annotated with the `json` tag"
+	json.NewEncoder(nil).Encode(st)  // want "the given struct should be annotated with the `json` tag"
+	json.Unmarshal(nil, &st)         // want "the given struct should be annotated with the `json` tag"
+	json.NewDecoder(nil).Decode(&st) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,14 @@
+package decode
+
+import "encoding/json"
+
+type Struct struct{ NoTag string }
+
+func test() {
+	var st Struct
+	json.Marshal(st)
+	json.MarshalIndent(st, "", "")
+	json.NewEncoder(nil).Encode(st)
+	json.Unmarshal(nil, &st)         // want "the given struct should be annotated with the `json` tag"
+	json.NewDecoder(nil).Decode(&st) // want "the given struct should be annotated with the `json` tag"
+}
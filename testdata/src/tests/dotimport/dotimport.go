@@ -0,0 +1,22 @@
+package dotimport
+
+import (
+	"encoding/json"
+
+	. "tests/dotimport/models"
+)
+
+type User struct {
+	Name    string  `json:"name"`
+	Address Address `json:"address"`
+}
+
+type Local struct {
+	Street string
+}
+
+func test() {
+	json.Marshal(User{})    // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Address{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Local{})   // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,5 @@
+package models
+
+type Address struct {
+	Street string
+}
@@ -0,0 +1,58 @@
+package elements
+
+import "encoding/json"
+
+type Address struct {
+	City string
+}
+
+type Meta struct {
+	Key string
+}
+
+type Point struct {
+	X int
+}
+
+type Tagged struct {
+	Name string `json:"name"`
+}
+
+type Slices struct {
+	Addresses []Address `json:"addresses"`
+}
+
+type Maps struct {
+	Tags map[string]Meta `json:"tags"`
+}
+
+type Arrays struct {
+	Points [3]Point `json:"points"`
+}
+
+type Wrapped struct {
+	Nested *[]map[string][2]*Address `json:"nested"`
+}
+
+type Tree struct {
+	Name     string           `json:"name"`
+	Children []Tree           `json:"children"`
+	Parent   *Tree            `json:"parent"`
+	Index    map[string]*Tree `json:"index"`
+}
+
+type Clean struct {
+	Items  []Tagged          `json:"items"`
+	ByName map[string]Tagged `json:"by_name"`
+	Pairs  [2]*Tagged        `json:"pairs"`
+	Keys   map[Meta]Tagged   `json:"keys"` // the keys are only checked with -check-map-keys.
+}
+
+func test() {
+	json.Marshal(Slices{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Maps{})    // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Arrays{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Wrapped{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Tree{})
+	json.Marshal(Clean{})
+}
@@ -0,0 +1,28 @@
+package off
+
+import (
+	"encoding/json"
+
+	"example.com/custom"
+)
+
+type Document struct {
+	Title string `json:"title"`
+	custom.Metadata
+}
+
+type Entry struct {
+	Title string `json:"title"`
+	custom.Record
+}
+
+type Wrapper struct {
+	Title    string          `json:"title"`
+	Metadata custom.Metadata `json:"metadata"`
+}
+
+func test() {
+	json.Marshal(Document{})
+	json.Marshal(Entry{})
+	json.Marshal(Wrapper{})
+}
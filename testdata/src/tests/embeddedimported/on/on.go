@@ -0,0 +1,54 @@
+package on
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"example.com/custom"
+	"gopkg.in/yaml.v3"
+)
+
+type Document struct {
+	Title string `json:"title"`
+	custom.Metadata
+}
+
+type Entry struct {
+	Title string `json:"title"`
+	custom.Record
+}
+
+type Wrapper struct {
+	Title    string          `json:"title"`
+	Metadata custom.Metadata `json:"metadata"`
+}
+
+type Labeled struct {
+	Title string `json:"title"`
+	fmt.Stringer
+}
+
+// the well-known types of the standard library are leaves even when embedded.
+type Link struct {
+	Title string `json:"title"`
+	url.URL
+	sync.Mutex
+}
+
+// yaml.Node holds a raw yaml document, but is a plain struct for other encoders.
+type Manifest struct {
+	Name string `json:"name" yaml:"name"`
+	yaml.Node
+}
+
+func test() {
+	json.Marshal(Document{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Entry{})
+	json.Marshal(Wrapper{})
+	json.Marshal(Labeled{})
+	json.Marshal(&Link{})
+	yaml.Marshal(Manifest{})
+	json.Marshal(Manifest{}) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,15 @@
+package emptytags
+
+import "encoding/json"
+
+type User struct {
+	Empty   string `json:""` // want "the `json` tag of field Empty is empty"
+	Options string `json:",omitempty"`
+	Name    string `json:"name"`
+	Other   string `json:"other" yaml:""`
+}
+
+func test() {
+	json.Marshal(User{})
+	json.Marshal(&User{})
+}
@@ -0,0 +1,23 @@
+package envprefix
+
+import "github.com/caarlos0/env/v11"
+
+type Database struct {
+	Host string `env:"HOST"`
+}
+
+type Config struct {
+	Name     string    `env:"NAME"`
+	Database Database  `envPrefix:"DB_"`
+	Replica  *Database // want "the nested struct field Replica should be annotated with the `envPrefix` tag"
+	Tags     []string  `env:"TAGS"`
+}
+
+type Leaf struct {
+	Port int
+}
+
+func test() {
+	env.Parse(&Config{})
+	env.Parse(&Leaf{}) // want "the given struct should be annotated with the `env` tag"
+}
@@ -0,0 +1,34 @@
+package facts
+
+import (
+	"encoding/json"
+
+	"tests/facts/payload"
+	"tests/facts/wrap"
+)
+
+type Local struct {
+	Name string
+}
+
+func decodeLocal(data []byte) (any, error) { // want decodeLocal:"returns \\*tests/facts.Local"
+	return &Local{}, nil
+}
+
+func test(data []byte) {
+	event, err := payload.Decode(data) // want "the given struct should be annotated with the `json` tag"
+	if err != nil {
+		return
+	}
+	json.Marshal(event) // want "the given struct should be annotated with the `json` tag"
+
+	loaded, _ := wrap.Load(data) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(loaded)         // want "the given struct should be annotated with the `json` tag"
+
+	local, _ := decodeLocal(data)
+	json.Marshal(local) // want "the given struct should be annotated with the `json` tag"
+
+	unknown, _ := payload.DecodeAny(data)
+	payload.DecodeAny(data)
+	json.Marshal(unknown)
+}
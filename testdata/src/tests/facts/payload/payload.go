@@ -0,0 +1,35 @@
+package payload
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+type Event struct {
+	ID   string `json:"id"`
+	Kind string
+}
+
+type Ping struct {
+	Seq int
+}
+
+// Decode returns the event behind an interface, e.g. to fit a generic handler.
+func Decode(data []byte) (any, error) {
+	var e Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// DecodeAny returns different structs, so its result cannot be traced.
+func DecodeAny(data []byte) (any, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty")
+	}
+	if data[0] == 'p' {
+		return Ping{}, nil
+	}
+	return Event{}, nil
+}
@@ -0,0 +1,8 @@
+package wrap
+
+import "tests/facts/payload"
+
+// Load is a wrapper of another package, so the fact is passed along.
+func Load(data []byte) (any, error) {
+	return payload.Decode(data)
+}
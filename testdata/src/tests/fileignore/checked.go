@@ -0,0 +1,15 @@
+package fileignore
+
+import "encoding/json"
+
+// the directive is only recognized above the package clause.
+//musttag:file-ignore
+
+type User struct {
+	Name string
+}
+
+func test() {
+	json.Marshal(User{})   // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Legacy{}) // want "the given struct should be annotated with the `json` tag"
+}
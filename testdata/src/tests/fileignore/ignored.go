@@ -0,0 +1,16 @@
+// Legacy structs, to be annotated later.
+
+//musttag:file-ignore
+
+package fileignore
+
+import "encoding/json"
+
+type Legacy struct {
+	Name string
+}
+
+func legacy() {
+	json.Marshal(Legacy{})
+	json.Marshal(User{})
+}
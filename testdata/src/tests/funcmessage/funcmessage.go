@@ -0,0 +1,17 @@
+package funcmessage
+
+import (
+	"encoding/json"
+
+	"example.com/custom"
+)
+
+type Event struct {
+	Name string
+}
+
+func test() {
+	custom.Marshal(Event{}) // want "add a log tag to the fields of the event"
+	json.Marshal(Event{})   // want "the given struct should be annotated with the `json` tag"
+	custom.Encode(Event{})  // want "use the log tag: see the logging guide"
+}
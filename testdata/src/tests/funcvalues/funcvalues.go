@@ -0,0 +1,44 @@
+package funcvalues
+
+import (
+	"encoding/json"
+	"os"
+)
+
+type User struct {
+	Name string
+}
+
+var globalMarshal = json.Marshal
+
+func test(cond bool) {
+	marshal := json.Marshal
+	marshal(User{}) // want "the given struct should be annotated with the `json` tag"
+
+	var unmarshal = json.Unmarshal
+	unmarshal(nil, &User{}) // want "the given struct should be annotated with the `json` tag"
+
+	encode := json.NewEncoder(os.Stdout).Encode
+	encode(User{}) // want "the given struct should be annotated with the `json` tag"
+
+	dec := json.NewDecoder(os.Stdin)
+	decode := (dec.Decode)
+	decode(&User{}) // want "the given struct should be annotated with the `json` tag"
+
+	// the origin is ambiguous.
+	reassigned := json.Marshal
+	if cond {
+		reassigned = func(any) ([]byte, error) { return nil, nil }
+	}
+	reassigned(User{})
+
+	// the receiver is the first argument.
+	methodExpr := (*json.Encoder).Encode
+	methodExpr(nil, User{})
+
+	// not a local variable.
+	globalMarshal(User{})
+
+	closure := func(v any) ([]byte, error) { return json.Marshal(v) }
+	closure(User{})
+}
@@ -0,0 +1,7 @@
+package logf // want "the functions may be called"
+
+import "encoding/json"
+
+func JSON(payload any) {
+	_, _ = json.Marshal(payload)
+}
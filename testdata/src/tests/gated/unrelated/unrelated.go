@@ -0,0 +1,11 @@
+package unrelated
+
+import "strings"
+
+type Payload struct {
+	Message string
+}
+
+func test() {
+	_ = strings.ToUpper(Payload{}.Message)
+}
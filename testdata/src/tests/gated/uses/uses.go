@@ -0,0 +1,11 @@
+package uses // want "the functions may be called"
+
+import "tests/gated/logf"
+
+type Payload struct {
+	Message string
+}
+
+func test() {
+	logf.JSON(Payload{})
+}
@@ -0,0 +1,26 @@
+package gopg
+
+import "github.com/go-pg/pg/v10"
+
+type User struct {
+	ID   int64 `pg:"id,pk"`
+	Name string
+}
+
+type Book struct {
+	ID    int64  `pg:"id,pk"`
+	Title string `pg:"title"`
+}
+
+func test(db *pg.DB) {
+	var user User
+	db.Model(&user).Insert()                    // want "the given struct should be annotated with the `pg` tag"
+	db.Model(&user).Where("id = ?", 1).Select() // want "the given struct should be annotated with the `pg` tag"
+
+	var users []User
+	db.Model(&users).Select() // want "the given struct should be annotated with the `pg` tag"
+
+	var books []Book
+	db.Model(&books).Select()
+	db.Model(&user, &books).Select() // want "the given struct should be annotated with the `pg` tag"
+}
@@ -0,0 +1,33 @@
+package ignoretypes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+type Handle struct {
+	ID string
+}
+
+type Service struct {
+	Name   string `json:"name"`
+	Ctx    context.Context
+	DB     *sql.DB
+	Client http.Client
+	Out    io.Writer
+	Handle *Handle
+}
+
+type Config struct {
+	Name string `json:"name"`
+	DB   *sql.DB
+	Addr string
+}
+
+func test() {
+	json.Marshal(Service{})
+	json.Marshal(Config{}) // want "the given struct should be annotated with the `json` tag"
+}
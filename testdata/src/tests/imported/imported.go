@@ -0,0 +1,17 @@
+package imported
+
+import (
+	"encoding/json"
+
+	"tests/imported/models"
+)
+
+type Order struct {
+	ID    string `json:"id"`
+	Total int
+}
+
+func test() {
+	json.Marshal(Order{})       // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(models.User{}) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,6 @@
+package models
+
+type User struct {
+	Name  string `json:"name"`
+	Email string
+}
@@ -0,0 +1,13 @@
+package incomplete
+
+import "encoding/json"
+
+type Struct struct{ NoTag string }
+
+func test() {
+	var st Struct
+	json.Marshal()
+	json.Unmarshal(nil)
+	json.NewDecoder(nil).Decode()
+	json.Marshal(st) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,12 @@
+package instances
+
+import "example.com/custom"
+
+type Struct struct {
+	A string `a:"a" c:"a"`
+	B string `a:"b"`
+}
+
+func test() {
+	custom.Marshal(Struct{})
+}
@@ -0,0 +1,41 @@
+package leavesonly
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Database struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type Server struct {
+	Addr    string        `json:"addr"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+type Config struct {
+	Database Database
+	Server   *Server
+}
+
+type BadServer struct {
+	Addr string
+}
+
+type BadConfig struct {
+	Server BadServer
+}
+
+type LeafConfig struct {
+	Database Database `json:"database"`
+	Servers  []Server
+	Created  time.Time
+}
+
+func test() {
+	json.Marshal(Config{})
+	json.Marshal(BadConfig{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(LeafConfig{}) // want "the given struct should be annotated with the `json` tag"
+}
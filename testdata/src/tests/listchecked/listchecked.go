@@ -0,0 +1,30 @@
+package listchecked
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+type User struct { // want "checked User for the `json` tag" "checked User for the `xml` tag"
+	Name    string   `json:"name" xml:"name"`
+	Address *Address `json:"address" xml:"address"`
+}
+
+type Address struct { // want "checked Address for the `json` tag" "checked Address for the `xml` tag"
+	City string `json:"city" xml:"city"`
+}
+
+type Account struct { // want "checked Account for the `json` tag"
+	ID string
+}
+
+func test() {
+	json.Marshal(User{})
+	json.Marshal([]*User{})
+	xml.Marshal(User{})
+	json.Marshal(Account{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(struct {   // want "checked an anonymous struct for the `json` tag"
+		Name string `json:"name"`
+	}{})
+	json.Marshal(map[string]int{})
+}
@@ -0,0 +1,28 @@
+package off
+
+import "encoding/json"
+
+type Key struct {
+	Region string `json:"region"`
+	Zone   string
+}
+
+type Value struct {
+	Count int `json:"count"`
+}
+
+type TaggedKey struct {
+	Region string `json:"region"`
+}
+
+type Stats struct {
+	ByKey map[Key]Value `json:"by_key"`
+}
+
+func test() {
+	json.Marshal(map[Key]Value{})
+	json.Marshal([]map[Key]Value{})
+	json.Marshal(Stats{})
+	json.Marshal(map[TaggedKey]Value{})
+	json.Marshal(map[string]Value{})
+}
@@ -0,0 +1,29 @@
+package depth2
+
+import "encoding/json"
+
+type Level1 struct {
+	Name   string `json:"name"`
+	Level2 Level2 `json:"level2"`
+}
+
+type Level2 struct {
+	Name   string `json:"name"`
+	Level3 Level3 `json:"level3"`
+}
+
+type Level3 struct {
+	NoTag string
+}
+
+type Shallow struct {
+	Level2 struct {
+		NoTag string
+	} `json:"level2"`
+}
+
+func test() {
+	json.Marshal(Level1{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Level2{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Shallow{}) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,18 @@
+package methodargs
+
+import (
+	"os"
+
+	"example.com/custom"
+)
+
+type User struct {
+	Name string
+}
+
+func test() {
+	var codec custom.StreamCodec
+	codec.Encode(os.Stdout, User{})                         // want "the given struct should be annotated with the `custom` tag"
+	codec.Decode(os.Stdin, &User{})                         // want "the given struct should be annotated with the `custom` tag"
+	(*custom.StreamCodec).Encode(&codec, os.Stdout, User{}) // want "the given struct should be annotated with the `custom` tag"
+}
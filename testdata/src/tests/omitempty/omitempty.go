@@ -0,0 +1,39 @@
+package omitempty
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+type User struct {
+	Name      string            `json:"name,omitempty"`
+	Address   Address           `json:"address,omitempty"` // want "the \"omitempty\" option of field Address has no effect on a struct type"
+	Billing   *Address          `json:"billing,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt time.Time         `json:"created_at,omitempty"` // want "the \"omitempty\" option of field CreatedAt has no effect on a struct type"
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"`
+	Shipping  Address           `json:"shipping"`
+}
+
+type Envelope struct {
+	Meta struct { // want "the \"omitempty\" option of field Meta has no effect on a struct type"
+		Source string `json:"source"`
+	} `json:",omitempty"`
+}
+
+type Address struct {
+	City string `json:"city" xml:"city"`
+}
+
+type Account struct {
+	Address Address `xml:"address,omitempty"`
+}
+
+func test() {
+	json.Marshal(User{})
+	json.Marshal(&User{})
+	json.Marshal(Envelope{})
+	xml.Marshal(Account{})
+}
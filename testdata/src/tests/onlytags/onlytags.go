@@ -0,0 +1,18 @@
+package onlytags
+
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Struct struct{ NoTag string }
+
+func test() {
+	var st Struct
+	json.Marshal(st) // want "the given struct should be annotated with the `json` tag"
+	xml.Marshal(st)  // want "the given struct should be annotated with the `xml` tag"
+	yaml.Marshal(st)
+	yaml.Unmarshal(nil, &st)
+}
@@ -0,0 +1,26 @@
+package perdirection
+
+import "example.com/custom"
+
+func test() {
+	type EncodeOnly struct {
+		Field string `enc:"field"`
+	}
+	var eo EncodeOnly
+	custom.Marshal(eo)
+	custom.Unmarshal(nil, &eo) // want "the given struct should be annotated with the `dec` tag"
+
+	type DecodeOnly struct {
+		Field string `dec:"field"`
+	}
+	var do DecodeOnly
+	custom.Marshal(do) // want "the given struct should be annotated with the `enc` tag"
+	custom.Unmarshal(nil, &do)
+
+	type Both struct {
+		Field string `enc:"field" dec:"field"`
+	}
+	var both Both
+	custom.Marshal(both)
+	custom.Unmarshal(nil, &both)
+}
@@ -0,0 +1,30 @@
+package perfield
+
+import (
+	"encoding/json"
+
+	"tests/imported/models"
+)
+
+type User struct {
+	Name    string   `json:"name"`
+	Email   string   // want "the field Email should be annotated with the `json` tag"
+	Address Address  // want "the field Address should be annotated with the `json` tag"
+	Tags    []string `json:"tags"`
+}
+
+type Address struct {
+	Street string `json:"street"`
+	City   string // want "the field City should be annotated with the `json` tag"
+}
+
+type Order struct {
+	ID   string `json:"id"`
+	User User   `json:"user"`
+}
+
+func test() {
+	json.Marshal(User{})
+	json.Marshal(Order{})
+	json.Marshal(models.User{}) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,11 @@
+package related
+
+import "encoding/json"
+
+func encode() {
+	json.Marshal(User{})
+}
+
+func decode() {
+	json.Unmarshal(nil, &User{})
+}
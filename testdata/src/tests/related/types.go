@@ -0,0 +1,6 @@
+package related
+
+type User struct {
+	Name  string `json:"name"`
+	Alias string `json:"name"` // want "the `json` tag name \"name\" of field Alias is already used by field Name"
+}
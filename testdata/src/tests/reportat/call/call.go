@@ -0,0 +1,18 @@
+package reportat
+
+import (
+	"encoding/json"
+
+	"tests/reportat/models"
+)
+
+type User struct {
+	Name  string
+	Email string `json:"email"`
+}
+
+func test() {
+	json.Marshal(User{})           // want `the field Name should be annotated with the .json. tag \(in User\)`
+	json.Marshal(&User{})          // want `the field Name should be annotated with the .json. tag \(in \*User\)`
+	json.Marshal(models.Address{}) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,36 @@
+package requireany
+
+import "encoding/json"
+
+type Untagged struct {
+	ID   int
+	Name string
+}
+
+type Partial struct {
+	ID   int `json:"id"`
+	Name string
+}
+
+type Tagged struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Nested struct {
+	ID    int      `json:"id"`
+	Inner Untagged `json:"inner"`
+}
+
+type UnexportedTagged struct {
+	id   int `json:"id"`
+	Name string
+}
+
+func test() {
+	json.Marshal(Untagged{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Partial{})
+	json.Marshal(Tagged{})
+	json.Marshal(Nested{})           // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(UnexportedTagged{}) // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,25 @@
+package requireoption
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+type User struct {
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email"`      // want "the `json` tag of field Email should have the \"omitempty\" option"
+	Age     int    `json:"age,string"` // want "the `json` tag of field Age should have the \"omitempty\" option"
+	Phone   string `json:"phone,string,omitempty"`
+	Skip    string `json:"-"`
+	Address string `json:",omitempty" xml:"address"`
+}
+
+type Account struct {
+	ID string `xml:"id"` // want "the `xml` tag of field ID should have the \"omitempty\" option"
+}
+
+func test() {
+	json.Marshal(User{})
+	json.Marshal(&User{})
+	xml.Marshal(Account{})
+}
@@ -0,0 +1,26 @@
+package resultpos
+
+import "example.com/custom"
+
+type User struct {
+	Name string
+}
+
+type Tagged struct {
+	Name string `json:"name"`
+}
+
+func decodeUser([]byte) (User, error) { return User{}, nil }
+
+func decodeUsers([]byte) []User { return nil }
+
+func test() {
+	decodeUser(nil)          // want "the given struct should be annotated with the `json` tag"
+	decodeUsers(nil)         // want "the given struct should be annotated with the `json` tag"
+	custom.Decode[User](nil) // want "the given struct should be annotated with the `json` tag"
+	custom.Decode[Tagged](nil)
+	custom.Decode[*Tagged](nil)
+
+	u, err := decodeUser(nil) // want "the given struct should be annotated with the `json` tag"
+	_, _ = u, err
+}
@@ -0,0 +1,23 @@
+package mixed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+// Event has a custom json encoding, yet it is still shared with xml.
+type Event struct{ Name string }
+
+func (Event) MarshalJSON() ([]byte, error) { return nil, nil }
+
+// Feed has a custom xml encoding, yet it is still shared with json.
+type Feed struct{ Title string }
+
+func (Feed) MarshalXML(*xml.Encoder, xml.StartElement) error { return nil }
+
+func encode(e Event, f Feed) {
+	json.Marshal(e) // want "the given struct should be annotated with the `xml` tag"
+	json.Marshal(f) // want "the given struct should be annotated with the `json` tag"
+	xml.Marshal(e)  // want "the given struct should be annotated with the `xml` tag"
+	xml.Marshal(f)  // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,11 @@
+package onlyxml
+
+import "encoding/json"
+
+type Event struct {
+	Name string `json:"name"`
+}
+
+func encode(e Event) {
+	json.Marshal(e) // want "the given struct should be annotated with the `xml` tag"
+}
@@ -0,0 +1,42 @@
+package sharedtags
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is served by the API as json, read from a yaml file, and read from a legacy toml file.
+type Config struct {
+	Name     string   `json:"name" yaml:"name" toml:"name"`
+	Listen   string   `json:"listen" yaml:"listen" toml:"address"` // want "the `toml` tag name \"address\" of field Listen does not match the `json` tag name \"listen\""
+	LogLevel string   `json:"log_level" yaml:"log_level"`          // want "the field LogLevel should be annotated with the `toml` tag"
+	Debug    bool     `json:"debug" toml:"debug"`                  // want "the field Debug should be annotated with the `yaml` tag"
+	Database Database `json:"database" yaml:"database" toml:"database"`
+}
+
+type Database struct {
+	DSN      string `json:"dsn" yaml:"dsn" toml:"dsn"`
+	MaxConns int    `json:"max_conns" yaml:"maxConns" toml:"max_conns"` // want "the `yaml` tag name \"maxConns\" of field MaxConns does not match the `json` tag name \"max_conns\""
+	Timeout  int    // want "the field Timeout should be annotated with the `json` tag" "the field Timeout should be annotated with the `yaml` tag" "the field Timeout should be annotated with the `toml` tag"
+}
+
+// Message is encoded by an unrelated encoder, so the shared tags are not required.
+type Message struct {
+	Body string `xml:"body"`
+}
+
+func serve(cfg Config, msg Message) {
+	json.Marshal(cfg)
+	xml.Marshal(msg)
+}
+
+func load(path string) (cfg Config) {
+	data, _ := os.ReadFile(path)
+	yaml.Unmarshal(data, &cfg)
+	toml.Decode(string(data), &cfg)
+	return cfg
+}
@@ -0,0 +1,40 @@
+package siblings
+
+import "encoding/json"
+
+type Valid struct {
+	Name string `json:"name"`
+}
+
+type InvalidA struct {
+	A string
+}
+
+type InvalidB struct {
+	B string
+}
+
+// the first nested struct that misses the tag is reported, regardless of the valid siblings around it.
+type First struct {
+	X Valid    `json:"x"`
+	Y InvalidA `json:"y"`
+	Z InvalidB `json:"z"`
+}
+
+type Second struct {
+	X Valid    `json:"x"`
+	Y Valid    `json:"y"`
+	Z InvalidB `json:"z"`
+}
+
+type Third struct {
+	X InvalidA `json:"x"`
+	Y Valid    `json:"y"`
+}
+
+func test() {
+	json.Marshal(First{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Second{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Third{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Valid{})
+}
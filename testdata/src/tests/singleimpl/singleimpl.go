@@ -0,0 +1,37 @@
+package singleimpl
+
+import "encoding/json"
+
+type Event interface{ event() }
+
+type Created struct {
+	ID   string `json:"id"`
+	Name string
+}
+
+func (*Created) event() {}
+
+type Shape interface{ area() float64 }
+
+type Square struct{ Side float64 }
+
+func (Square) area() float64 { return 0 }
+
+type Circle struct{ Radius float64 }
+
+func (Circle) area() float64 { return 0 }
+
+type Tagged interface{ tagged() }
+
+type Note struct {
+	Text string `json:"text"`
+}
+
+func (Note) tagged() {}
+
+func test(e Event, s Shape, t Tagged, v any) {
+	json.Marshal(e) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(s)
+	json.Marshal(t)
+	json.Marshal(v)
+}
@@ -0,0 +1,27 @@
+package off
+
+import (
+	"encoding/json"
+	"net/url"
+	"sync"
+
+	"example.com/custom"
+)
+
+// the well-known types of the standard library are checked with the -no-default-skip-types flag.
+type Link struct {
+	Title string `json:"title"`
+	url.URL
+	sync.Mutex
+}
+
+// custom.Metadata is still a leaf via the -skip-types flag.
+type Document struct {
+	Title string `json:"title"`
+	custom.Metadata
+}
+
+func test() {
+	json.Marshal(&Link{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Document{})
+}
@@ -0,0 +1,27 @@
+package on
+
+import (
+	"encoding/json"
+	"net/url"
+	"sync"
+
+	"example.com/custom"
+)
+
+// the well-known types of the standard library are leaves by default.
+type Link struct {
+	Title string `json:"title"`
+	url.URL
+	sync.Mutex
+}
+
+// custom.Metadata is a leaf via the -skip-types flag.
+type Document struct {
+	Title string `json:"title"`
+	custom.Metadata
+}
+
+func test() {
+	json.Marshal(&Link{})
+	json.Marshal(Document{})
+}
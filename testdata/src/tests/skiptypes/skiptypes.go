@@ -0,0 +1,44 @@
+package skiptypes
+
+import "github.com/mitchellh/mapstructure"
+
+// Duration is converted from a string by a decoder hook.
+type Duration struct {
+	Value int64
+}
+
+// URL is converted from a string by a decoder hook.
+type URL struct {
+	Scheme string
+	Host   string
+}
+
+type Config struct {
+	Timeout  Duration    `mapstructure:"timeout"`
+	Endpoint *URL        `mapstructure:"endpoint"`
+	Mirrors  []URL       `mapstructure:"mirrors"`
+	Retry    RetryConfig `mapstructure:"retry"`
+}
+
+type RetryConfig struct {
+	Backoff Duration `mapstructure:"backoff"`
+	Max     int
+}
+
+type Untagged struct {
+	Timeout Duration
+}
+
+func test() {
+	var cfg Config
+	mapstructure.Decode(nil, &cfg) // want "the given struct should be annotated with the `mapstructure` tag"
+
+	var retry RetryConfig
+	mapstructure.Decode(nil, &retry) // want "the given struct should be annotated with the `mapstructure` tag"
+
+	var untagged Untagged
+	mapstructure.Decode(nil, &untagged) // want "the given struct should be annotated with the `mapstructure` tag"
+
+	var d Duration
+	mapstructure.Decode(nil, &d)
+}
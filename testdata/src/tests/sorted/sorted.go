@@ -0,0 +1,25 @@
+package sorted
+
+import "encoding/json"
+
+type First struct {
+	A string // want "the field A should be annotated with the `json` tag"
+	B string `json:"b"`
+	C string `json:"b"` // want "the `json` tag name \"b\" of field C is already used by field B"
+}
+
+type Second struct {
+	D string // want "the field D should be annotated with the `json` tag"
+	E Third  // want "the field E should be annotated with the `json` tag"
+}
+
+type Third struct {
+	F string // want "the field F should be annotated with the `json` tag"
+	G string // want "the field G should be annotated with the `json` tag"
+}
+
+func test() {
+	json.Marshal(Third{})
+	json.Marshal(Second{})
+	json.Marshal(First{})
+}
@@ -0,0 +1,24 @@
+package on
+
+import "github.com/mitchellh/mapstructure"
+
+type Config struct {
+	Name   string `mapstructure:"name"`
+	Server Server
+}
+
+type Server struct {
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"`
+}
+
+type Broken struct {
+	Server struct {
+		Host string
+	}
+}
+
+func test() {
+	mapstructure.Decode(nil, &Config{}) // want "the given struct should be annotated with the `mapstructure` tag"
+	mapstructure.Decode(nil, &Broken{}) // want "the given struct should be annotated with the `mapstructure` tag"
+}
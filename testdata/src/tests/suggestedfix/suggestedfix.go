@@ -0,0 +1,36 @@
+package suggestedfix
+
+import (
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/gin-gonic/gin"
+)
+
+type User struct {
+	Email string
+}
+
+type Account struct {
+	Email string
+}
+
+type Server struct {
+	HTTPServerID string `yaml:"http_server_id"`
+}
+
+type Request struct {
+	RequestID string
+}
+
+type Legacy struct {
+	A, B string
+}
+
+func test(c *gin.Context) {
+	json.Marshal(User{})     // want "the given struct should be annotated with the `json` tag"
+	xml.Marshal(Account{})   // want "the given struct should be annotated with the `xml` tag"
+	json.Marshal(Server{})   // want "the given struct should be annotated with the `json` tag"
+	c.BindHeader(&Request{}) // want "the given struct should be annotated with the `header` tag"
+	json.Marshal(Legacy{})   // want "the given struct should be annotated with the `json` tag"
+}
@@ -0,0 +1,11 @@
+package tagcase
+
+import "encoding/json"
+
+type User struct {
+	UserID string
+}
+
+func test() {
+	json.Marshal(User{}) // want "the given struct should be annotated with the `json` tag"
+}
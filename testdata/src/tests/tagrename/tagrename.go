@@ -0,0 +1,22 @@
+package tagrename
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+type Renamed struct {
+	Name string `j:"name"`
+	Age  int    `j:"age"`
+}
+
+type Standard struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func test() {
+	json.Marshal(Renamed{})
+	json.Unmarshal(nil, &Renamed{})
+	json.Marshal(Standard{}) // want "the given struct should be annotated with the `j` tag"
+	xml.Marshal(Standard{})
+}
@@ -0,0 +1,28 @@
+package templates
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+)
+
+type Page struct {
+	Title string `template:"title"`
+	Body  string
+}
+
+type Tagged struct {
+	Title string `template:"title"`
+}
+
+func test(tmpl *template.Template, html *htmltemplate.Template) {
+	tmpl.Execute(nil, Page{})              // want "the given struct should be annotated with the `template` tag"
+	tmpl.ExecuteTemplate(nil, "", &Page{}) // want "the given struct should be annotated with the `template` tag"
+	html.Execute(nil, Page{})              // want "the given struct should be annotated with the `template` tag"
+
+	var data any = Page{}
+	tmpl.Execute(nil, data) // want "the given struct should be annotated with the `template` tag"
+
+	var tagged any = &Tagged{}
+	tmpl.Execute(nil, tagged)
+	html.ExecuteTemplate(nil, "", tagged)
+}
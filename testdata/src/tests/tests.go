@@ -1,6 +1,11 @@
 package tests
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
 
 func namedType() {
 	type Foo struct {
@@ -23,6 +28,30 @@ func anonymousType() {
 	json.Marshal(&struct{ NoTag int }{}) // want "the given struct should be annotated with the `json` tag"
 }
 
+type handler struct{}
+
+func (*handler) handleRequest() {
+	var resp struct {
+		Email string
+	}
+	json.Marshal(resp) // want `the given struct should be annotated with the .json. tag \(missing on field "Email" of the anonymous struct in handler.handleRequest\)`
+	func() {
+		json.Marshal(resp) // want `the given struct should be annotated with the .json. tag \(missing on field "Email" of the anonymous struct in handler.handleRequest\)`
+	}()
+}
+
+func anonymousTypeWithNamedNestedType() {
+	type Bar struct {
+		NoTag string
+	}
+	var foo struct {
+		Bar Bar `json:"bar"`
+	}
+	json.Marshal(foo) // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of Bar\)`
+}
+
+var _, _ = json.Marshal(struct{ NoTag int }{}) // want "the given struct should be annotated with the `json` tag"
+
 func nestedType() {
 	type Bar struct {
 		NoTag string
@@ -51,6 +80,28 @@ func embeddedType() {
 	json.Marshal(&Foo{}) // want "the given struct should be annotated with the `json` tag"
 }
 
+func embeddedPointerType() {
+	type Bar struct {
+		NoTag string
+	}
+	type Foo struct {
+		*Bar
+	}
+	var foo Foo
+	json.Marshal(foo)    // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(&foo)   // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Foo{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(&Foo{}) // want "the given struct should be annotated with the `json` tag"
+
+	type Baz struct {
+		Tag string `json:"tag"`
+	}
+	type Qux struct {
+		*Baz
+	}
+	json.Marshal(Qux{})
+}
+
 func nestedArrayType() {
 	type Bar struct {
 		NoTag string
@@ -125,7 +176,7 @@ func shouldBeIgnored() {
 		NoTag int
 	}
 	var foo Foo
-	marshalJSON := json.Marshal
+	var marshalJSON func(any) ([]byte, error)
 	marshalJSON(foo)  // a non-static call.
 	json.Marshal(0)   // a non-struct argument.
 	json.Marshal(nil) // nil argument, see issue #20.
@@ -185,3 +236,486 @@ func interfaceSliceType() {
 	json.MarshalIndent(withMarshallableSlice, "", "")
 	json.NewEncoder(nil).Encode(withMarshallableSlice)
 }
+
+func basicUnderlyingTypes() {
+	type ID string
+	type Count int
+	type Foo struct {
+		Number json.Number `json:"number"`
+		ID     ID          `json:"id"`
+		Count  Count       `json:"count"`
+	}
+	var foo Foo
+	json.Marshal(foo)
+	json.Marshal(&foo)
+	json.Unmarshal(nil, &foo)
+	json.Marshal(json.Number("1"))
+	json.Marshal(ID("id"))
+	json.Marshal(Count(1))
+}
+
+func inlineLiterals() {
+	type Foo struct {
+		NoTag string
+	}
+	json.Marshal([]Foo{ // want "the given struct should be annotated with the `json` tag"
+		{NoTag: "a"},
+	})
+	json.Marshal(map[string]Foo{ // want "the given struct should be annotated with the `json` tag"
+		"a": {NoTag: "a"},
+	})
+	json.Marshal([]struct{ NoTag string }{
+		{NoTag: "a"}, // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in inlineLiterals\)`
+		{NoTag: "b"},
+	})
+	json.Marshal(&[1]*struct{ NoTag string }{
+		{NoTag: "a"}, // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in inlineLiterals\)`
+	})
+	json.Marshal(map[string]struct{ NoTag string }{
+		"a": {NoTag: "a"}, // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in inlineLiterals\)`
+	})
+	json.Marshal([]struct{ NoTag string }{}) // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in inlineLiterals\)`
+}
+
+func anonymousElements() {
+	json.Marshal(map[string]struct{ NoTag string }{})   // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in anonymousElements\)`
+	json.Marshal(map[string][]struct{ NoTag string }{}) // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in anonymousElements\)`
+	json.Unmarshal(nil, &[]struct{ NoTag string }{})    // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in anonymousElements\)`
+
+	json.Marshal([]struct { // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in anonymousElements\)`
+		Inner struct{ NoTag string } `json:"inner"`
+	}{})
+	json.Marshal([]struct {
+		Tag string `json:"tag"`
+	}{})
+
+	var users []struct {
+		NoTag string
+	}
+	json.Marshal(users) // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in anonymousElements\)`
+}
+
+func nonStructDecodeTargets() {
+	var anyMap map[string]any
+	json.Unmarshal(nil, &anyMap)
+	json.NewDecoder(nil).Decode(&anyMap)
+
+	var intMap map[string]int
+	json.Unmarshal(nil, &intMap)
+	json.NewDecoder(nil).Decode(&intMap)
+
+	var anySlice []any
+	json.Unmarshal(nil, &anySlice)
+	json.NewDecoder(nil).Decode(&anySlice)
+}
+
+func nestedPointerType() {
+	type Bar struct {
+		NoTag string
+	}
+	type BarPtr *Bar
+	type Foo struct {
+		Bar    *Bar   `json:"bar"`
+		BarPtr BarPtr `json:"bar_ptr"`
+	}
+	type FooPtr struct {
+		BarPtr BarPtr `json:"bar_ptr"`
+	}
+	json.Marshal(Foo{})    // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(FooPtr{}) // want "the given struct should be annotated with the `json` tag"
+}
+
+func namedPointerType() {
+	type User struct {
+		NoTag string
+	}
+	type UserRef *User
+	type UserRefPtr *UserRef
+	var ref UserRef
+	json.Marshal(ref)              // want "the given struct should be annotated with the `json` tag"
+	json.Unmarshal(nil, &ref)      // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(UserRefPtr(&ref)) // want "the given struct should be annotated with the `json` tag"
+
+	type TaggedUser struct {
+		Tag string `json:"tag"`
+	}
+	type TaggedUserRef *TaggedUser
+	var tagged TaggedUserRef
+	json.Marshal(tagged)
+}
+
+// the named pointers pointing to themselves, which have no struct to check.
+type (
+	SelfRef   *SelfRef
+	CycleRefA *CycleRefB
+	CycleRefB *CycleRefA
+	CycleRefC *[]CycleRefC
+)
+
+func namedPointerCycle() {
+	var self SelfRef
+	json.Marshal(self)
+	json.Unmarshal(nil, &self)
+
+	var cycle CycleRefA
+	json.Marshal(cycle)
+	json.Marshal([]CycleRefB{})
+
+	var nested CycleRefC
+	json.Marshal(nested)
+}
+
+func multiReturnVariable() {
+	type Foo struct {
+		NoTag string
+	}
+	get := func() (Foo, error) { return Foo{}, nil }
+	foo, err := get()
+	_ = err
+	json.Marshal(foo)  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(&foo) // want "the given struct should be annotated with the `json` tag"
+}
+
+func untaggedNestedType() {
+	type Bar struct {
+		Tagged string `json:"tagged"`
+	}
+	type Foo struct {
+		Bar Bar
+	}
+	json.Marshal(Foo{}) // want "the given struct should be annotated with the `json` tag"
+}
+
+func interfaceField() {
+	type Foo struct {
+		Err   error
+		Value any
+		Tag   string `json:"tag"`
+	}
+	json.Marshal(Foo{})
+	json.Unmarshal(nil, &Foo{})
+}
+
+// a method-only interface promotes no fields, so there is nothing to annotate or to look into.
+func embeddedInterface() {
+	type Foo struct {
+		fmt.Stringer
+		Tag string `json:"tag"`
+	}
+	type Bar struct {
+		fmt.Stringer
+		NoTag string
+	}
+	json.Marshal(Foo{})
+	json.Unmarshal(nil, &Foo{})
+	json.Marshal(Bar{}) // want "the given struct should be annotated with the `json` tag"
+}
+
+type aliasedStruct struct {
+	NoTag string
+}
+
+type structAlias = aliasedStruct
+
+func aliasType() {
+	type Foo struct {
+		Alias structAlias `json:"alias"`
+	}
+	type Bar struct {
+		Alias *structAlias `json:"alias"`
+	}
+	json.Marshal(structAlias{})  // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(&structAlias{}) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Foo{})          // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Bar{})          // want "the given struct should be annotated with the `json` tag"
+}
+
+type Event interface{ isEvent() }
+
+type UserEvent struct{ UserID string }
+
+func (UserEvent) isEvent() {}
+
+type OrderEvent struct {
+	OrderID string `json:"order_id"`
+}
+
+func (*OrderEvent) isEvent() {}
+
+func interfaceSliceLiteral() {
+	json.Marshal([]Event{
+		UserEvent{}, // want "the given struct should be annotated with the `json` tag"
+		&OrderEvent{},
+	})
+	json.Marshal(map[string]any{
+		"user":  UserEvent{}, // want "the given struct should be annotated with the `json` tag"
+		"order": OrderEvent{},
+		"count": 1,
+	})
+
+	var events []Event
+	json.Marshal(events)
+	json.Marshal([]Event{events[0]})
+}
+
+func localInterfaceValue() {
+	type Foo struct {
+		NoTag string
+	}
+	type Bar struct {
+		Tag string `json:"tag"`
+	}
+
+	var foo any = &Foo{}
+	json.NewDecoder(nil).Decode(foo) // want "the given struct should be annotated with the `json` tag"
+	json.Unmarshal(nil, foo)         // want "the given struct should be annotated with the `json` tag"
+
+	var bar any = &Bar{}
+	json.Unmarshal(nil, bar)
+
+	var iface interface{}
+	iface = Foo{}
+	json.Marshal(iface) // want "the given struct should be annotated with the `json` tag"
+
+	// ambiguous: assigned twice.
+	var twice any = &Bar{}
+	twice = &Foo{}
+	json.Unmarshal(nil, twice)
+
+	// ambiguous: the address is taken.
+	var ptr any = &Foo{}
+	reassign(&ptr)
+	json.Unmarshal(nil, ptr)
+
+	// ambiguous: assigned from a function with several results.
+	multi, _ := target()
+	json.Unmarshal(nil, multi)
+}
+
+func reassign(*any) {}
+
+func target() (any, error) { return nil, nil }
+
+func exemptType() {
+	type Bar struct {
+		_     struct{} `musttag:"-"`
+		NoTag string
+	}
+	type Foo struct {
+		Bar   Bar `json:"bar"`
+		NoTag string
+		_     struct{} `musttag:"-"`
+	}
+	json.Marshal(Foo{})
+	json.Marshal(Bar{})
+	type Baz struct {
+		Bar   Bar `json:"bar"`
+		Other string
+	}
+	json.Marshal(Baz{}) // want "the given struct should be annotated with the `json` tag"
+}
+
+func selectorArg() {
+	type Payload struct {
+		NoTag string
+	}
+	type Response struct {
+		Payload Payload `json:"payload"`
+		Meta    struct {
+			NoTag string
+		} `json:"meta"`
+		Tagged struct {
+			Tag string `json:"tag"`
+		} `json:"tagged"`
+	}
+	var resp Response
+	json.NewEncoder(nil).Encode(resp.Payload)  // want "the given struct should be annotated with the `json` tag"
+	json.NewEncoder(nil).Encode(&resp.Payload) // want "the given struct should be annotated with the `json` tag"
+	json.NewEncoder(nil).Encode(resp.Meta)     // want `the given struct should be annotated with the .json. tag \(missing on field "NoTag" of the anonymous struct in selectorArg\)`
+	json.NewEncoder(nil).Encode(resp.Tagged)
+}
+
+func deferredCall() {
+	type Response struct {
+		NoTag string
+	}
+	var resp Response
+	enc := json.NewEncoder(nil)
+	defer enc.Encode(resp) // want "the given struct should be annotated with the `json` tag"
+	go enc.Encode(resp)    // want "the given struct should be annotated with the `json` tag"
+	defer func() {
+		enc.Encode(&resp) // want "the given struct should be annotated with the `json` tag"
+	}()
+}
+
+func indexedArg() {
+	type Item struct {
+		NoTag string
+	}
+	var items []Item
+	var array [3]Item
+	var byName map[string]Item
+	var ptrs map[string]*Item
+	i := 0
+	json.Unmarshal(nil, &items[i]) // want "the given struct should be annotated with the `json` tag"
+	json.Unmarshal(nil, &array[1]) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(byName["k"])      // want "the given struct should be annotated with the `json` tag"
+	json.Unmarshal(nil, ptrs["k"]) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(items[0:1])       // want "the given struct should be annotated with the `json` tag"
+}
+
+func namedCollections(i int) {
+	type User struct {
+		NoTag string
+	}
+	type Users []User
+	type Index map[string]User
+	type Groups [][]User
+	var users Users
+	var index Index
+	var groups Groups
+	json.Marshal(users[i])     // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(index["k"])   // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(groups[i][0]) // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(users[i].NoTag)
+	json.Marshal((users)[i]) // want "the given struct should be annotated with the `json` tag"
+}
+
+func reflectTargets[T any](data []byte) (T, error) {
+	type Foo struct {
+		NoTag string
+	}
+	// the targets are built at run time, so their types are unknown.
+	ptr := reflect.New(reflect.TypeOf(Foo{})).Interface()
+	json.Unmarshal(data, ptr)
+	json.Unmarshal(data, reflect.New(reflect.TypeOf(new(T)).Elem()).Interface())
+
+	target := reflect.ValueOf(new(T)).Interface()
+	err := json.Unmarshal(data, target)
+	return *target.(*T), err
+}
+
+func unexportedFields() {
+	// the struct is (un)marshaled as {}, so there is nothing to annotate.
+	type private struct {
+		name string
+		age  int
+	}
+	json.Marshal(private{})
+	json.Marshal(&private{})
+	json.Unmarshal(nil, &private{})
+	json.Marshal([]private{})
+	json.Marshal(struct{ id int }{})
+	json.Marshal(struct{}{})
+}
+
+func statementContexts(w io.Writer) error {
+	type Foo struct {
+		NoTag string
+	}
+	var v Foo
+	if err := json.NewEncoder(w).Encode(v); err != nil { // want "the given struct should be annotated with the `json` tag"
+		return err
+	}
+	switch err := json.Unmarshal(nil, &v); { // want "the given struct should be annotated with the `json` tag"
+	case err != nil:
+		return err
+	}
+	switch data, err := json.Marshal(v); err { // want "the given struct should be annotated with the `json` tag"
+	case nil:
+		_ = data
+	}
+	for _, err := json.Marshal(&v); err != nil; { // want "the given struct should be annotated with the `json` tag"
+		break
+	}
+	return nil
+}
+
+type Envelope[T any] struct {
+	Data T      `json:"data"`
+	Meta string `json:"meta"`
+}
+
+func Wrap[T any](v T) Envelope[T] { return Envelope[T]{Data: v} }
+
+func genericWrappers() {
+	type User struct {
+		Name string
+	}
+	json.Marshal(Wrap(User{}))               // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Wrap(&User{}))              // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Wrap([]User{}))             // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Envelope[User]{})           // want "the given struct should be annotated with the `json` tag"
+	json.Marshal(Wrap(Wrap(User{})))         // want "the given struct should be annotated with the `json` tag"
+	json.Unmarshal(nil, new(Envelope[User])) // want "the given struct should be annotated with the `json` tag"
+
+	type Tagged struct {
+		Name string `json:"name"`
+	}
+	json.Marshal(Wrap(Tagged{}))
+	json.Marshal(Wrap(1))
+	json.Marshal(Envelope[any]{})
+}
+
+// the "avoid infinite recursion" idiom: the methods delegate to the encoder via a type without them.
+type Account struct {
+	ID    string `json:"id"`
+	Email string
+}
+
+func (a Account) MarshalJSON() ([]byte, error) {
+	type account Account
+	return json.Marshal(account(a)) // want "the given struct should be annotated with the `json` tag"
+}
+
+func (a *Account) UnmarshalJSON(data []byte) error {
+	type account Account
+	return json.Unmarshal(data, (*account)(a)) // want "the given struct should be annotated with the `json` tag"
+}
+
+type Profile struct {
+	Name string `json:"name"`
+}
+
+func (p Profile) MarshalJSON() ([]byte, error) {
+	type profile Profile
+	return json.Marshal(struct {
+		profile
+		Kind string `json:"kind"`
+	}{profile(p), "profile"})
+}
+
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	type profile Profile
+	aux := struct {
+		*profile
+		Kind string
+	}{profile: (*profile)(p)}
+	return json.Unmarshal(data, &aux) // want `the given struct should be annotated with the .json. tag \(missing on field "Kind" of the anonymous struct in Profile.UnmarshalJSON\)`
+}
+
+func marshalerDelegation() {
+	json.Marshal(Account{})
+	json.Unmarshal(nil, &Account{})
+	json.Marshal(Profile{})
+}
+
+// the element is resolved by its type, no matter how the index is computed.
+func appendedElement(data [][]byte) {
+	type Item struct {
+		NoTag string
+	}
+	type Tagged struct {
+		Tag string `json:"tag"`
+	}
+	var items []Item
+	var tagged []Tagged
+	for _, b := range data {
+		items = append(items, Item{})
+		json.Unmarshal(b, &items[len(items)-1]) // want "the given struct should be annotated with the `json` tag"
+
+		tagged = append(tagged, Tagged{})
+		json.Unmarshal(b, &tagged[len(tagged)-1])
+		json.Unmarshal(b, &tagged[cap(tagged)-len(tagged)+len(b)%2])
+	}
+}
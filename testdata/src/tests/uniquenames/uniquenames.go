@@ -0,0 +1,69 @@
+package uniquenames
+
+import (
+	"encoding/json"
+
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v3"
+)
+
+type SameLevel struct {
+	Name  string `json:"name"`
+	Alias string `json:"name"`           // want "the `json` tag name \"name\" of field Alias is already used by field Name"
+	Other string `json:"name,omitempty"` // want "the `json` tag name \"name\" of field Other is already used by field Name"
+	Dash  string `json:"-,"`
+	Title string `json:"-,"` // want "the `json` tag name \"-\" of field Title is already used by field Dash"
+	Skip  string `json:"-"`
+	Skip2 string `json:"-"`
+}
+
+type Base struct {
+	ID string `json:"id" mapstructure:"id" yaml:"id"`
+}
+
+type Inlined struct {
+	Base
+	ID string `json:"id"` // want "the `json` tag name \"id\" of field ID is already used by field ID"
+}
+
+type PointerInlined struct {
+	*Base
+	ID string `json:"id"` // want "the `json` tag name \"id\" of field ID is already used by field ID"
+}
+
+type Named struct {
+	Base `json:"base"`
+	ID   string `json:"id"`
+}
+
+type Squashed struct {
+	Base `mapstructure:",squash"`
+	ID   string `mapstructure:"id"` // want "the `mapstructure` tag name \"id\" of field ID is already used by field ID"
+}
+
+type YAMLInlined struct {
+	Base `yaml:",inline"`
+	ID   string `yaml:"id"` // want "the `yaml` tag name \"id\" of field ID is already used by field ID"
+}
+
+type YAMLFlow struct {
+	Tags  []string `yaml:"tags,flow"`
+	Names []string `yaml:"tags"` // want "the `yaml` tag name \"tags\" of field Names is already used by field Tags"
+}
+
+type Unique struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+func test() {
+	json.Marshal(SameLevel{})
+	json.Marshal(&SameLevel{})
+	json.Marshal(Inlined{})
+	json.Marshal(PointerInlined{})
+	json.Marshal(Named{})
+	json.Marshal(Unique{})
+	mapstructure.Decode(nil, &Squashed{})
+	yaml.Marshal(YAMLInlined{})
+	yaml.Marshal(YAMLFlow{})
+}
@@ -0,0 +1,14 @@
+package verbose
+
+import (
+	"encoding/json"
+
+	"example.com/custom"
+)
+
+type Struct struct{ NoTag string }
+
+func test() {
+	json.Marshal(Struct{})   // want "the given struct should be annotated with the `json` tag \\(triggered by encoding/json.Marshal at verbose.go:12\\)"
+	custom.Marshal(Struct{}) // want "the given struct should be annotated with the `custom` tag \\(triggered by example.com/custom.Marshal at verbose.go:13\\)"
+}
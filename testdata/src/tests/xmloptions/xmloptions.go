@@ -0,0 +1,28 @@
+package xmloptions
+
+import "encoding/xml"
+
+type Text struct {
+	Value string
+}
+
+type Options struct {
+	XMLName  xml.Name `xml:"options"`
+	ID       string   `xml:"id,attr"`
+	Attr     string   `xml:",attr"`
+	CharData Text     `xml:",chardata"`
+	CData    Text     `xml:",cdata"`
+	InnerXML Text     `xml:",innerxml"`
+	Comment  Text     `xml:",comment"`
+	Omit     string   `xml:",omitempty"`
+}
+
+type Any struct {
+	Elems []Text `xml:",any"`
+}
+
+func test() {
+	xml.Marshal(Options{})
+	xml.Unmarshal(nil, &Options{})
+	xml.Marshal(Any{}) // want "the given struct should be annotated with the `xml` tag"
+}
@@ -3,11 +3,37 @@ package musttag
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 )
 
+// mainModules caches the main module by the working directory,
+// since the analyzer runs on every dependency of the analyzed packages to export facts.
+var mainModules sync.Map // working directory -> module path.
+
 func getMainModule() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if path, ok := mainModules.Load(wd); ok {
+		return path.(string), nil
+	}
+
+	path, err := readMainModule()
+	if err != nil {
+		return "", err
+	}
+	mainModules.Store(wd, path)
+	return path, nil
+}
+
+func readMainModule() (string, error) {
 	args := [...]string{"go", "mod", "edit", "-json"}
 
 	out, err := exec.Command(args[0], args[1:]...).Output()
@@ -47,3 +73,146 @@ func cutVendor(path string) string {
 
 	return prefix + path
 }
+
+// funcName normalizes the full name of a function, so that it can be used as a key:
+// the vendor prefix is cut and the type parameters of a generic receiver are omitted,
+// e.g. "(*vendor/example.com/foo.Codec[T]).Encode" -> "(*example.com/foo.Codec).Encode".
+func funcName(name string) string {
+	name = cutVendor(name)
+	if i := strings.Index(name, "["); i >= 0 {
+		if j := strings.LastIndex(name, "]"); j > i {
+			name = name[:i] + name[j+1:]
+		}
+	}
+	return name
+}
+
+// hasOption reports whether the tag value has the given option, e.g. "inline" in `yaml:",inline"`.
+func hasOption(tagValue, option string) bool {
+	_, options, _ := strings.Cut(tagValue, ",")
+	for options != "" {
+		var opt string
+		opt, options, _ = strings.Cut(options, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyKey reports whether the struct tag has at least one well-formed key:"value" pair, e.g. `yaml:"name"`.
+// Like [reflect.StructTag.Lookup], it does not look past a malformed pair.
+func hasAnyKey(structTag string) bool {
+	tag := strings.TrimLeft(structTag, " ")
+	i := 0
+	for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+		i++
+	}
+	if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+		return false
+	}
+	_, err := strconv.QuotedPrefix(tag[i+1:])
+	return err == nil
+}
+
+// funcPkgPath returns the package path of a function name normalized by [funcName],
+// e.g. "(*example.com/foo.Codec).Encode" -> "example.com/foo".
+func funcPkgPath(name string) string {
+	name = strings.TrimPrefix(name, "(")
+	name = strings.TrimPrefix(name, "*")
+	if i := strings.Index(name, ")"); i >= 0 {
+		name = name[:i] // the receiver type.
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// CaseStyle is a naming convention of tag names, e.g. snake_case.
+type CaseStyle int
+
+const (
+	SnakeCase  CaseStyle = iota // user_id
+	CamelCase                   // userID
+	PascalCase                  // UserID
+	KebabCase                   // user-id
+	LowerCase                   // userid
+)
+
+var caseStyles = map[string]CaseStyle{
+	"snake":  SnakeCase,
+	"camel":  CamelCase,
+	"pascal": PascalCase,
+	"kebab":  KebabCase,
+	"lower":  LowerCase,
+}
+
+// TagNameFor converts the name of a field to a tag name of the given style, e.g. "UserID" -> "user_id".
+// Acronyms and numbers are kept together, e.g. "HTTPServer2" -> "http_server2".
+// In camelCase, acronyms are words like any other, e.g. "UserID" -> "userId" and "HTTPServer" -> "httpServer",
+// while PascalCase keeps them as Go names do, e.g. "UserID" -> "UserID".
+// It is the convention of the suggested fixes.
+func TagNameFor(fieldName string, style CaseStyle) string {
+	if fieldName == "" {
+		return ""
+	}
+	words := splitWords(fieldName)
+	switch style {
+	case CamelCase:
+		for i := 1; i < len(words); i++ {
+			words[i] = upperFirst(strings.ToLower(words[i]))
+		}
+		words[0] = strings.ToLower(words[0])
+		return strings.Join(words, "")
+	case PascalCase:
+		for i := range words {
+			words[i] = upperFirst(words[i])
+		}
+		return strings.Join(words, "")
+	case KebabCase:
+		return strings.ToLower(strings.Join(words, "-"))
+	case LowerCase:
+		return strings.ToLower(strings.Join(words, ""))
+	default:
+		return strings.ToLower(strings.Join(words, "_"))
+	}
+}
+
+// upperFirst converts the first letter of the word to upper case, e.g. "at" -> "At".
+func upperFirst(word string) string {
+	if word == "" {
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+// splitWords splits a Go identifier into words, keeping acronyms and numbers together,
+// e.g. "HTTPServerID2" -> ["HTTP", "Server", "ID2"].
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		switch {
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+		case unicode.IsUpper(cur) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+		case cur == '_':
+		default:
+			continue
+		}
+		if word := strings.Trim(string(runes[start:i]), "_"); word != "" {
+			words = append(words, word)
+		}
+		start = i
+	}
+	if word := strings.Trim(string(runes[start:]), "_"); word != "" {
+		words = append(words, word)
+	}
+	if len(words) == 0 {
+		return []string{name}
+	}
+	return words
+}
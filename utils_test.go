@@ -23,3 +23,115 @@ func Test_cutVendor(t *testing.T) {
 		assert.Equal[E](t, got, test.want)
 	}
 }
+
+func Test_funcName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"foo/bar.A", "foo/bar.A"},
+		{"vendor/foo/bar.A", "foo/bar.A"},
+		{"(foo/bar.T).M", "(foo/bar.T).M"},
+		{"(*foo/bar.T[E]).M", "(*foo/bar.T).M"},
+		{"(*test/vendor/foo/bar.T[K, V]).M", "(*foo/bar.T).M"},
+	}
+
+	for _, test := range tests {
+		got := funcName(test.name)
+		assert.Equal[E](t, got, test.want)
+	}
+}
+
+func Test_funcPkgPath(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"encoding/json.Marshal", "encoding/json"},
+		{"(*encoding/json.Decoder).Decode", "encoding/json"},
+		{"(example.com/foo.T).M", "example.com/foo"},
+		{"(*gopkg.in/yaml.v3.Encoder).Encode", "gopkg.in/yaml.v3"},
+	}
+
+	for _, test := range tests {
+		got := funcPkgPath(test.name)
+		assert.Equal[E](t, got, test.want)
+	}
+}
+
+func Test_hasOption(t *testing.T) {
+	tests := []struct {
+		tagValue, option string
+		want             bool
+	}{
+		{"", "squash", false},
+		{"squash", "squash", false},
+		{",squash", "squash", true},
+		{"name,omitempty,squash", "squash", true},
+		{",omitempty", "squash", false},
+		{",squashed", "squash", false},
+	}
+
+	for _, test := range tests {
+		got := hasOption(test.tagValue, test.option)
+		assert.Equal[E](t, got, test.want)
+	}
+}
+
+func Test_hasAnyKey(t *testing.T) {
+	tests := []struct {
+		structTag string
+		want      bool
+	}{
+		{"", false},
+		{" ", false},
+		{`json:"id"`, true},
+		{` yaml:""`, true},
+		{`db:"id" json:"id"`, true},
+		{`yaml:id`, false},
+		{`:"id"`, false},
+		{`json:"id`, false},
+		{`json`, false},
+	}
+
+	for _, test := range tests {
+		got := hasAnyKey(test.structTag)
+		assert.Equal[E](t, got, test.want)
+	}
+}
+
+func TestTagNameFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		style CaseStyle
+		want  string
+	}{
+		{"Name", SnakeCase, "name"},
+		{"UserID", SnakeCase, "user_id"},
+		{"UserID", CamelCase, "userId"},
+		{"UserID", PascalCase, "UserID"},
+		{"UserID", KebabCase, "user-id"},
+		{"UserID", LowerCase, "userid"},
+		{"ID", SnakeCase, "id"},
+		{"ID", CamelCase, "id"},
+		{"URL", KebabCase, "url"},
+		{"HTTPServer", SnakeCase, "http_server"},
+		{"HTTPServer", CamelCase, "httpServer"},
+		{"ProfileURL", CamelCase, "profileUrl"},
+		{"HTTPServerID", CamelCase, "httpServerId"},
+		{"HTTPServer", PascalCase, "HTTPServer"},
+		{"ProfileURL", KebabCase, "profile-url"},
+		{"Address2", SnakeCase, "address2"},
+		{"V2API", SnakeCase, "v2_api"},
+		{"Line2Text", SnakeCase, "line2_text"},
+		{"created_at", CamelCase, "createdAt"},
+		{"created_at", PascalCase, "CreatedAt"},
+		{"X", SnakeCase, "x"},
+		{"", SnakeCase, ""},
+		{"", CamelCase, ""},
+		{"", PascalCase, ""},
+	}
+
+	for _, test := range tests {
+		got := TagNameFor(test.name, test.style)
+		assert.Equal[E](t, got, test.want)
+	}
+}